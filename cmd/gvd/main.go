@@ -0,0 +1,143 @@
+// Command gvd 是 go-vdom 的命令列工具
+//
+// 用法：
+//
+//	gvd build [-pkg ./site] [-out dist] [-static static] [-base-url https://example.com] [-force]
+//	gvd dev [-pkg .] [-dir .] [-addr 127.0.0.1:35729] [-app localhost:8080] [-- args...]
+//	gvd elements [-out gvd-elements.js]
+//
+// build 會以 `go run` 執行 -pkg 指定的網站程式（其 main 函數需呼叫 site.Main），
+// 其餘參數原樣轉交給該程式。
+//
+// dev 會建置並執行 -pkg 指定的伺服器程式，監看 -dir 下的 .go 檔案，
+// 變更後重新建置、重啟，並通知瀏覽器重新載入。
+//
+// elements 將 components 套件的組件匯出為原生 Web Component（自訂元素）的 JavaScript bundle，
+// 讓不使用 Go 的專案也能以 <gvd-card> 等標籤使用；-out 為 "-" 時輸出到標準輸出。
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+
+	"github.com/TimLai666/go-vdom/components"
+	"github.com/TimLai666/go-vdom/gvd/devserver"
+	"github.com/TimLai666/go-vdom/webcomponent"
+)
+
+// command 是一個子命令
+type command struct {
+	name  string
+	usage string
+	run   func(args []string) error
+}
+
+var commands = []command{
+	{name: "build", usage: "render every registered page to a static output directory", run: runBuild},
+	{name: "dev", usage: "run the server, rebuild on .go changes and live-reload browsers", run: runDev},
+	{name: "elements", usage: "write the components package as a custom element JavaScript bundle", run: runElements},
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	name := os.Args[1]
+	for _, cmd := range commands {
+		if cmd.name == name {
+			if err := cmd.run(os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "gvd %s: %v\n", name, err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
+	if name != "help" && name != "-h" && name != "--help" {
+		fmt.Fprintf(os.Stderr, "gvd: unknown command %q\n", name)
+	}
+	usage()
+	os.Exit(2)
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: gvd <command> [flags]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-8s %s\n", cmd.name, cmd.usage)
+	}
+}
+
+// runBuild 以 go run 執行網站程式，由其呼叫 site.Main 完成建置
+func runBuild(args []string) error {
+	flags := flag.NewFlagSet("build", flag.ContinueOnError)
+	pkg := flags.String("pkg", ".", "Go package whose main calls site.Main")
+	out := flags.String("out", "dist", "output directory")
+	static := flags.String("static", "", "static asset directory copied into the output")
+	baseURL := flags.String("base-url", "", "site base URL used for sitemap.xml")
+	force := flags.Bool("force", false, "rewrite every page even if unchanged")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	siteArgs := []string{"run", *pkg, "-out", *out}
+	if *static != "" {
+		siteArgs = append(siteArgs, "-static", *static)
+	}
+	if *baseURL != "" {
+		siteArgs = append(siteArgs, "-base-url", *baseURL)
+	}
+	if *force {
+		siteArgs = append(siteArgs, "-force")
+	}
+
+	cmd := exec.Command("go", siteArgs...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// runDev 啟動開發伺服器，直到收到中斷訊號
+func runDev(args []string) error {
+	flags := flag.NewFlagSet("dev", flag.ContinueOnError)
+	var cfg devserver.Config
+	flags.StringVar(&cfg.Pkg, "pkg", ".", "main package of the server to run")
+	flags.StringVar(&cfg.Dir, "dir", ".", "project directory watched for .go changes")
+	flags.StringVar(&cfg.Addr, "addr", "127.0.0.1:35729", "listen address of the live-reload endpoint")
+	flags.StringVar(&cfg.AppAddr, "app", "", "address of the server; reload waits until it accepts connections (default: browsers poll the page until it responds)")
+	flags.DurationVar(&cfg.Interval, "interval", 0, "file polling interval (default 500ms)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	cfg.Args = flags.Args()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	return devserver.Run(ctx, cfg)
+}
+
+// runElements 產生 components 套件的自訂元素 bundle
+func runElements(args []string) error {
+	flags := flag.NewFlagSet("elements", flag.ContinueOnError)
+	out := flags.String("out", "gvd-elements.js", `output file, or "-" for stdout`)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	bundle, err := webcomponent.Bundle(components.Elements...)
+	if err != nil {
+		return err
+	}
+	if *out == "-" {
+		_, err = os.Stdout.WriteString(bundle)
+		return err
+	}
+	return os.WriteFile(*out, []byte(bundle), 0o644)
+}
@@ -4,6 +4,8 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/TimLai666/go-vdom/webcomponent"
+
 	. "github.com/TimLai666/go-vdom/dom"
 )
 
@@ -323,3 +325,15 @@ func TestHelpTextDisplay(t *testing.T) {
 		}
 	}
 }
+
+func TestElementsBundle(t *testing.T) {
+	js, err := webcomponent.Bundle(Elements...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, el := range Elements {
+		if !strings.Contains(js, `define("`+el.Tag+`", `) {
+			t.Errorf("bundle missing %s", el.Tag)
+		}
+	}
+}
@@ -0,0 +1,18 @@
+package components
+
+import "github.com/TimLai666/go-vdom/webcomponent"
+
+// Elements 列出可匯出為原生 Web Component 的組件，`gvd elements` 以此產生 JavaScript bundle
+// Btn 與 TextField 在 Component 之外計算衍生 props，無法匯出
+var Elements = []webcomponent.Element{
+	{Tag: "gvd-alert", Component: Alert},
+	{Tag: "gvd-card", Component: Card},
+	{Tag: "gvd-checkbox", Component: Checkbox},
+	{Tag: "gvd-checkbox-group", Component: CheckboxGroup},
+	{Tag: "gvd-dropdown", Component: Dropdown},
+	{Tag: "gvd-modal", Component: Modal},
+	{Tag: "gvd-radio", Component: Radio},
+	{Tag: "gvd-radio-group", Component: RadioGroup},
+	{Tag: "gvd-switch", Component: Switch},
+	{Tag: "gvd-table", Component: TableComponent},
+}
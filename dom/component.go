@@ -2,11 +2,15 @@
 package dom
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"regexp"
 	"strings"
 	"sync/atomic"
+
+	"github.com/TimLai666/go-vdom/internal/jsvalue"
 )
 
 var componentIDCounter uint64
@@ -29,28 +33,23 @@ type PropsDefault map[string]any
 // Component(template, &act, PropsDefault{"id":"", ...})
 // Component(template, nil, PropsDefault{"id":"", ...}) // 傳 nil 表示不注入 onDOMReadyCallback
 func Component(template VNode, onDOMReadyCallback *JSAction, defaultProps ...PropsDefault) func(props Props, children ...VNode) VNode {
-	return func(p Props, children ...VNode) VNode {
-		mergedProps := make(Props)
-
-		// 若有提供 defaultProps，合併進 mergedProps
-		if len(defaultProps) > 0 && defaultProps[0] != nil {
-			for k, v := range defaultProps[0] {
-				mergedProps[k] = v
-			}
-		}
-
-		// 合併使用者傳入的 props（Props 已為 map[string]interface{}）
-		for k, v := range p {
-			mergedProps[k] = v
-		}
+	// 水合模式使用的初始化函數在建立組件時編譯一次，所有實例共用
+	var init compiledInit
+	spec := &ComponentSpec{Template: template}
+	if onDOMReadyCallback != nil {
+		init = compileInit(onDOMReadyCallback.Code)
+		spec.OnDOMReady = onDOMReadyCallback.Code
+	}
+	if len(defaultProps) > 0 {
+		spec.Defaults = defaultProps[0]
+	}
 
-		// 如果沒有提供 id，生成一個穩定的唯一 id（便於組件內腳本綁定 container）
-		if idv, ok := mergedProps["id"]; !ok || strings.TrimSpace(fmt.Sprint(idv)) == "" {
-			mergedProps["id"] = genComponentID()
-		}
+	return func(p Props, children ...VNode) VNode {
+		mergedProps := mergeComponentProps(defaultProps, p)
 
 		// 使用模板與合併後的 props 產生 VNode (先進行模板插值)
 		node := interpolate(template, mergedProps, children)
+		node.Props[componentSpecKey] = spec
 
 		// 若提供了 onDOMReadyCallback（指標）且其內容非空，且使用者未透過 props 顯式覆寫 onDOMReadyCallback，則將其注入為 node.Props["onDOMReadyCallback"]
 		if onDOMReadyCallback != nil && strings.TrimSpace(onDOMReadyCallback.Code) != "" {
@@ -63,6 +62,7 @@ func Component(template VNode, onDOMReadyCallback *JSAction, defaultProps ...Pro
 				// 使用 interpolateStringForJS 保持 JSON 格式
 				interpolatedCode := interpolateStringForJS(onDOMReadyCallback.Code, mergedProps)
 				node.Props["onDOMReady"] = JSAction{Code: interpolatedCode}
+				node.Props[componentMetaKey] = init.meta(mergedProps, interpolatedCode)
 			}
 		}
 
@@ -70,6 +70,131 @@ func Component(template VNode, onDOMReadyCallback *JSAction, defaultProps ...Pro
 	}
 }
 
+// mergeComponentProps 依序合併預設 props 與使用者傳入的 props
+// 如果沒有提供 id，生成一個穩定的唯一 id（便於組件內腳本綁定 container）
+func mergeComponentProps(defaultProps []PropsDefault, p Props) Props {
+	mergedProps := make(Props)
+
+	// 若有提供 defaultProps，合併進 mergedProps
+	if len(defaultProps) > 0 && defaultProps[0] != nil {
+		for k, v := range defaultProps[0] {
+			mergedProps[k] = v
+		}
+	}
+
+	// 合併使用者傳入的 props（Props 已為 map[string]interface{}）
+	for k, v := range p {
+		mergedProps[k] = v
+	}
+
+	if idv, ok := mergedProps["id"]; !ok || strings.TrimSpace(fmt.Sprint(idv)) == "" {
+		mergedProps["id"] = genComponentID()
+	}
+	return mergedProps
+}
+
+// componentMetaKey 是 Component 存放水合資訊的保留屬性鍵
+// 以 "gvd:" 開頭的屬性屬於框架內部資料，不會被渲染為 HTML 屬性
+const componentMetaKey = "gvd:component"
+
+func isReservedProp(k string) bool {
+	return strings.HasPrefix(k, "gvd:")
+}
+
+// componentSpecKey 是 Component 存放組件定義的保留屬性鍵；與其他 "gvd:" 鍵相同，不會出現在 ToJSON 等輸出中
+const componentSpecKey = "gvd:spec"
+
+// ComponentSpec 是 Component 的原始定義（未插值的模板、onDOMReady 代碼與預設 props），
+// 供匯出為 Web Component 等工具使用
+type ComponentSpec struct {
+	Template   VNode
+	OnDOMReady string
+	Defaults   PropsDefault
+}
+
+// SpecOf 返回由 Component 建立的節點所屬組件的定義
+// 用法：spec, ok := SpecOf(Card(Props{}))
+func SpecOf(v VNode) (*ComponentSpec, bool) {
+	spec, ok := v.Props[componentSpecKey].(*ComponentSpec)
+	return spec, ok
+}
+
+// ShadowLookup 將代碼中 document 上的元素查找（getElementById / querySelector / querySelectorAll）
+// 改為在 root（shadow root 的 JavaScript 表達式）中查找，供以 shadow DOM 渲染的組件使用
+// 用法：ShadowLookup("document.getElementById('x')", "root") // root.getElementById('x')
+func ShadowLookup(code, root string) string {
+	return strings.NewReplacer(
+		"document.getElementById(", root+".getElementById(",
+		"document.querySelector(", root+".querySelector(",
+		"document.querySelectorAll(", root+".querySelectorAll(",
+	).Replace(code)
+}
+
+// ComponentMeta 是互動組件的水合資訊，RenderWith 在 Hydrate 模式下據此輸出標記
+type ComponentMeta struct {
+	Type  string         // 組件類型，同一類型的實例共用 Init
+	Init  string         // 以參數 p 讀取 props 的 onDOMReady 函數表達式
+	Props map[string]any // Init 讀取的 props；水合模式以 JSON 輸出為 data-gvd-p，收集模式以 JavaScript 字面量輸出
+}
+
+// propsJS 返回 props 的 JavaScript 字面量，與內聯的 {{key}} 插值使用相同的轉換（如 time.Time 為 Date）
+func (m ComponentMeta) propsJS() string {
+	if len(m.Props) == 0 {
+		return "{}"
+	}
+	return jsvalue.Encode(m.Props)
+}
+
+// propsJSON 返回 props 的 JSON，供 data-gvd-p 屬性使用；runtime 只以 JSON.parse 解析，
+// Date、Uint8Array 等值以 jsvalue.EncodeJSON 的標記物件表示並在解析時還原
+func (m ComponentMeta) propsJSON() string {
+	if len(m.Props) == 0 {
+		return "{}"
+	}
+	return jsvalue.EncodeJSON(m.Props)
+}
+
+// compiledInit 是編譯後的 onDOMReady 模板
+type compiledInit struct {
+	typ  string
+	code string   // {{key}} 已替換為 p["key"]；無法編譯時為空
+	keys []string // 代碼中引用的 props
+}
+
+var templateVarPattern = regexp.MustCompile(`\{\{(.+?)\}\}`)
+
+// compileInit 將 onDOMReady 代碼中的 {{key}} 轉為對參數 p 的引用，使同一組件的實例共用一份代碼
+// 含有 ${...} 表達式的代碼需在伺服器端求值，無法共用，此時每個實例各自註冊已插值的代碼
+func compileInit(code string) compiledInit {
+	sum := sha256.Sum256([]byte(code))
+	c := compiledInit{typ: "c" + hex.EncodeToString(sum[:4])}
+	if strings.Contains(code, "${") {
+		return c
+	}
+	seen := make(map[string]bool)
+	c.code = templateVarPattern.ReplaceAllStringFunc(code, func(match string) string {
+		key := strings.TrimSpace(match[2 : len(match)-2])
+		if !seen[key] {
+			seen[key] = true
+			c.keys = append(c.keys, key)
+		}
+		return "p[" + jsvalue.Encode(key) + "]"
+	})
+	return c
+}
+
+// meta 返回實例的水合資訊；interpolated 是該實例已插值的代碼，供無法共用時使用
+func (c compiledInit) meta(p Props, interpolated string) ComponentMeta {
+	if c.code == "" {
+		return ComponentMeta{Type: c.typ + "-" + fmt.Sprint(p["id"]), Init: interpolated}
+	}
+	props := make(map[string]any, len(c.keys))
+	for _, k := range c.keys {
+		props[k] = p[k]
+	}
+	return ComponentMeta{Type: c.typ, Init: c.code, Props: props}
+}
+
 // interpolate 替換模板中的變量
 func interpolate(template VNode, p Props, children []VNode) VNode {
 	newProps := make(Props)
@@ -183,7 +308,7 @@ func interpolateString(s string, p Props) string {
 		// 提取 ${} 內的表達式
 		expr := result[startIdx+2 : endIdx]
 		// 在表達式中替換 {{...}}
-		exprWithValues := replaceTemplateVarsInExpression(expr, p)
+		exprWithValues := replaceTemplateVarsInExpression(expr, p, serializeComplexType)
 		evaluated := evaluateExpression(strings.TrimSpace(exprWithValues))
 
 		// 替換整個 ${...} 為評估結果
@@ -213,14 +338,14 @@ func interpolateString(s string, p Props) string {
 	return result
 }
 
-// replaceTemplateVarsInExpression 在表達式中替換 {{...}} 為 JSON 值
-func replaceTemplateVarsInExpression(expr string, p Props) string {
+// replaceTemplateVarsInExpression 在表達式中以 encode 將 {{...}} 替換為字面量（字符串帶引號）
+// HTML 插值使用 serializeComplexType，JavaScript 代碼使用 jsvalue.Encode
+func replaceTemplateVarsInExpression(expr string, p Props, encode func(any) string) string {
 	re := regexp.MustCompile(`\{\{(.+?)\}\}`)
 	return re.ReplaceAllStringFunc(expr, func(match string) string {
 		key := strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(match, "{{"), "}}"))
 		if val, ok := p[key]; ok {
-			// 返回 JSON 格式（字符串帶引號）
-			return serializeComplexType(val)
+			return encode(val)
 		}
 		return "null"
 	})
@@ -462,7 +587,7 @@ func unquote(s string) string {
 	return s
 }
 
-// interpolateStringForJS 替換 JavaScript 代碼中的變量，保持 JSON 格式
+// interpolateStringForJS 替換 JavaScript 代碼中的變量；值一律以 jsvalue.Encode 轉為 JavaScript 字面量
 func interpolateStringForJS(s string, p Props) string {
 	// 先處理 ${...} 表達式
 	result := s
@@ -496,7 +621,7 @@ func interpolateStringForJS(s string, p Props) string {
 		// 提取 ${} 內的表達式
 		expr := result[startIdx+2 : endIdx]
 		// 在表達式中替換 {{...}}
-		exprWithValues := replaceTemplateVarsInExpression(expr, p)
+		exprWithValues := replaceTemplateVarsInExpression(expr, p, jsvalue.Encode)
 		evaluated := evaluateExpression(strings.TrimSpace(exprWithValues))
 
 		// 替換整個 ${...} 為評估結果
@@ -508,8 +633,8 @@ func interpolateStringForJS(s string, p Props) string {
 	result = re.ReplaceAllStringFunc(result, func(match string) string {
 		key := strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(match, "{{"), "}}"))
 		if val, ok := p[key]; ok {
-			// 在 JavaScript 代碼中，保持 JSON 格式（字符串帶引號）
-			return serializeComplexType(val)
+			// 在 JavaScript 代碼中輸出為字面量（字符串帶引號），並轉義 </script>、引號與 U+2028/U+2029
+			return jsvalue.Encode(val)
 		}
 		return "null"
 	})
@@ -0,0 +1,145 @@
+// diff.go
+package dom
+
+import "strings"
+
+// 補丁操作類型
+const (
+	PatchReplace  = "replace"  // 以 HTML 取代整個元素；路徑為空時取代根節點
+	PatchHTML     = "html"     // 以 HTML 取代元素的所有子節點
+	PatchAttrs    = "attrs"    // 依 HTML 中空元素的屬性同步元素屬性
+	PatchAppend   = "append"   // 將 HTML 附加到元素的子節點末端
+	PatchTruncate = "truncate" // 只保留元素前 Count 個子元素
+)
+
+// Patch 描述一次對瀏覽器 DOM 的更新
+// Path 是從根元素開始、逐層的子元素索引（對應 Element.children，不含文字節點）；
+// onDOMReady 產生的 <script> 也是子元素，會佔用一個索引
+type Patch struct {
+	Op    string `json:"op"`
+	Path  []int  `json:"path"`
+	HTML  string `json:"html,omitempty"`
+	Count int    `json:"count,omitempty"`
+}
+
+// Diff 比較兩棵樹並返回將 old 的渲染結果更新為 new 所需的補丁，兩者相同時返回 nil
+// 補丁需依序套用，路徑以套用前的 DOM 為準
+//   - 標籤不同或 onDOMReady 不同時取代整個元素
+//   - 含有文字內容的元素在渲染結果不同時整體取代子節點
+//   - 子元素只依位置比對；新增在末端的子元素以 append 加入，多出的以 truncate 移除
+//
+// 注意：未指定 id 的組件每次渲染都會得到新的自動 id，會使補丁退化為整段取代；
+// 需要細粒度更新的組件應提供固定的 id
+func Diff(old, new VNode) []Patch {
+	if old.Tag == "" || old.Tag != new.Tag || onDOMReadyCode(old) != onDOMReadyCode(new) {
+		if Render(old) == Render(new) {
+			return nil
+		}
+		return []Patch{{Op: PatchReplace, Path: []int{}, HTML: Render(new)}}
+	}
+	var patches []Patch
+	diffElement(&patches, []int{}, old, new)
+	return patches
+}
+
+func diffElement(patches *[]Patch, path []int, old, new VNode) {
+	if attrs := attrsHTML(new); attrsHTML(old) != attrs {
+		*patches = append(*patches, Patch{Op: PatchAttrs, Path: path, HTML: attrs})
+	}
+
+	// 文字與原始 HTML 內容無法以子元素索引定位，直接比較渲染結果
+	if hasTextContent(old) || hasTextContent(new) || !compatibleChildren(old.Children, new.Children) {
+		if inner := innerHTML(new); innerHTML(old) != inner {
+			*patches = append(*patches, Patch{Op: PatchHTML, Path: path, HTML: inner})
+		}
+		return
+	}
+
+	n := min(len(old.Children), len(new.Children))
+	index := 0
+	for i := 0; i < n; i++ {
+		oc, nc := old.Children[i], new.Children[i]
+		childPath := append(path[:len(path):len(path)], index)
+		if oc.Tag != nc.Tag {
+			*patches = append(*patches, Patch{Op: PatchReplace, Path: childPath, HTML: Render(nc)})
+		} else {
+			diffElement(patches, childPath, oc, nc)
+		}
+		index += elementCount(oc)
+	}
+
+	if len(new.Children) > n {
+		var sb strings.Builder
+		for _, c := range new.Children[n:] {
+			sb.WriteString(Render(c))
+		}
+		*patches = append(*patches, Patch{Op: PatchAppend, Path: path, HTML: sb.String()})
+	}
+	if len(old.Children) > n {
+		*patches = append(*patches, Patch{Op: PatchTruncate, Path: path, Count: index})
+	}
+}
+
+// compatibleChildren 判斷兩組子元素能否逐一比對
+// 同位置的子元素帶有不同 onDOMReady，或標籤不同且任一帶有 onDOMReady（其 <script> 不在元素內）時不行
+func compatibleChildren(old, new []VNode) bool {
+	for i := 0; i < min(len(old), len(new)); i++ {
+		oldReady, newReady := onDOMReadyCode(old[i]), onDOMReadyCode(new[i])
+		if oldReady != newReady || (old[i].Tag != new[i].Tag && oldReady != "") {
+			return false
+		}
+	}
+	return true
+}
+
+// hasTextContent 判斷元素是否直接包含文字節點或原始 HTML 內容
+func hasTextContent(v VNode) bool {
+	if v.Content != "" {
+		return true
+	}
+	for _, c := range v.Children {
+		if c.Tag == "" {
+			return true
+		}
+	}
+	return false
+}
+
+// elementCount 返回節點渲染後在父元素中佔用的子元素數量
+func elementCount(v VNode) int {
+	if onDOMReadyCode(v) != "" {
+		return 2
+	}
+	return 1
+}
+
+func onDOMReadyCode(v VNode) string {
+	switch t := v.Props["onDOMReady"].(type) {
+	case string:
+		return t
+	case JSAction:
+		return t.Code
+	}
+	return ""
+}
+
+// attrsHTML 渲染只有屬性、沒有子節點的元素，作為屬性比較與同步的依據
+func attrsHTML(v VNode) string {
+	props := make(Props, len(v.Props))
+	for k, val := range v.Props {
+		if k != "onDOMReady" {
+			props[k] = val
+		}
+	}
+	return Render(VNode{Tag: v.Tag, Props: props})
+}
+
+// innerHTML 渲染元素的內容與子節點
+func innerHTML(v VNode) string {
+	var sb strings.Builder
+	sb.WriteString(v.Content)
+	for _, c := range v.Children {
+		sb.WriteString(Render(c))
+	}
+	return sb.String()
+}
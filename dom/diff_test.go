@@ -0,0 +1,91 @@
+// diff_test.go
+package dom
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	item := func(s string) VNode { return Li(Props{"class": "item"}, s) }
+	ready := JSAction{Code: "function(){}"}
+
+	tests := []struct {
+		name string
+		old  VNode
+		new  VNode
+		want []Patch
+	}{
+		{
+			name: "identical",
+			old:  Div(Props{"id": "app"}, Ul(nil, item("a"))),
+			new:  Div(Props{"id": "app"}, Ul(nil, item("a"))),
+			want: nil,
+		},
+		{
+			name: "root tag changed",
+			old:  Div(nil, "x"),
+			new:  Section(nil, "x"),
+			want: []Patch{{Op: PatchReplace, Path: []int{}, HTML: "<section>x</section>"}},
+		},
+		{
+			name: "attribute and nested text",
+			old:  Div(Props{"class": "a"}, H1(nil, "t"), P(nil, "count: 1")),
+			new:  Div(Props{"class": "b"}, H1(nil, "t"), P(nil, "count: 2")),
+			want: []Patch{
+				{Op: PatchAttrs, Path: []int{}, HTML: `<div class="b"></div>`},
+				{Op: PatchHTML, Path: []int{1}, HTML: "count: 2"},
+			},
+		},
+		{
+			name: "child tag changed",
+			old:  Div(nil, P(nil, "a"), Span(nil, "b")),
+			new:  Div(nil, P(nil, "a"), Pre(nil, "b")),
+			want: []Patch{{Op: PatchReplace, Path: []int{1}, HTML: "<pre>b</pre>"}},
+		},
+		{
+			name: "append",
+			old:  Ul(nil, item("a")),
+			new:  Ul(nil, item("a"), item("b")),
+			want: []Patch{{Op: PatchAppend, Path: []int{}, HTML: `<li class="item">b</li>`}},
+		},
+		{
+			name: "truncate",
+			old:  Ul(nil, item("a"), item("b"), item("c")),
+			new:  Ul(nil, item("a")),
+			want: []Patch{{Op: PatchTruncate, Path: []int{}, Count: 1}},
+		},
+		{
+			name: "onDOMReady script shifts indexes",
+			old:  Div(nil, Div(Props{"onDOMReady": ready}), P(nil, "a")),
+			new:  Div(nil, Div(Props{"onDOMReady": ready}), P(nil, "b")),
+			want: []Patch{{Op: PatchHTML, Path: []int{2}, HTML: "b"}},
+		},
+		{
+			name: "onDOMReady changed",
+			old:  Div(nil, Div(Props{"onDOMReady": ready})),
+			new:  Div(nil, Div(Props{"onDOMReady": JSAction{Code: "function(){1}"}})),
+			want: []Patch{{Op: PatchHTML, Path: []int{}, HTML: "<div></div><script>(function(){var fn=function(){1};if(document.readyState==='loading'){document.addEventListener('DOMContentLoaded',fn);}else{fn();}})();</script>"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Diff(tt.old, tt.new); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Diff() =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindByID(t *testing.T) {
+	tree := Div(nil, Header(Props{"id": "top"}), Main(nil, Section(Props{"id": "content"}, P(nil, "x"))))
+
+	found, ok := FindByID(tree, "content")
+	if !ok || found.Tag != "section" || Render(found) != `<section id="content"><p>x</p></section>` {
+		t.Errorf("FindByID(content) = %v, %v", Render(found), ok)
+	}
+	if _, ok := FindByID(tree, "missing"); ok {
+		t.Error("FindByID should report missing ids")
+	}
+}
@@ -0,0 +1,96 @@
+// island.go
+package dom
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/TimLai666/go-vdom/internal/jsvalue"
+)
+
+// IslandStrategy 決定島嶼在客戶端何時初始化
+type IslandStrategy string
+
+const (
+	IslandLoad    IslandStrategy = "load"    // DOM 就緒後立即初始化
+	IslandIdle    IslandStrategy = "idle"    // 瀏覽器閒置時初始化（requestIdleCallback）
+	IslandVisible IslandStrategy = "visible" // 元素進入視窗時初始化（IntersectionObserver）
+)
+
+// islandMetaKey 是 Island 存放島嶼資訊的保留屬性鍵
+const islandMetaKey = "gvd:island"
+
+// IslandMeta 是島嶼實例的資訊，渲染時輸出為 data-gvd-island / data-gvd-props / data-gvd-load 屬性
+// Props 以 jsvalue.EncodeJSON 輸出為 JSON，runtime 只以 JSON.parse 解析並還原 Date、Uint8Array 等值
+type IslandMeta struct {
+	Name     string
+	Init     string
+	Strategy IslandStrategy
+	Props    map[string]any
+}
+
+// Island 創建一個互動島嶼組件
+// 與 Component 不同，初始化代碼不會在每個實例後內聯輸出，而是以 name 為鍵在頁面中只輸出一次：
+// Render 會在 </body> 前（沒有 body 時在結尾）輸出單一腳本，註冊頁面上用到的所有島嶼，
+// 再由 runtime 依各實例的策略呼叫 init(el, props)
+//   - name: 島嶼類型名稱，同名的島嶼必須使用相同的 init
+//   - template: 組件模板，與 Component 相同支援 {{key}} 插值
+//   - init: 接收根元素與 props 的函數表達式，例如 jsdsl.Fn([]string{"el", "props"}, ...)
+//   - strategy: 初始化時機，見 IslandLoad、IslandIdle、IslandVisible
+//   - defaultProps: 可選的預設 props；可序列化為 JSON 的 props 會傳給 init
+//
+// 用法：
+//
+//	var Counter = Island("counter",
+//	    Button(Props{"type": "button"}, "{{label}}"),
+//	    jsdsl.Fn([]string{"el", "props"}, JSAction{Code: "el.onclick = () => alert(props.label);"}),
+//	    IslandVisible,
+//	    PropsDefault{"label": "Click"},
+//	)
+func Island(name string, template VNode, init JSAction, strategy IslandStrategy, defaultProps ...PropsDefault) func(props Props, children ...VNode) VNode {
+	if strategy == "" {
+		strategy = IslandLoad
+	}
+	return func(p Props, children ...VNode) VNode {
+		mergedProps := mergeComponentProps(defaultProps, p)
+		node := interpolate(template, mergedProps, children)
+		if node.Props == nil {
+			node.Props = make(Props)
+		}
+		node.Props[islandMetaKey] = IslandMeta{
+			Name:     name,
+			Init:     init.Code,
+			Strategy: strategy,
+			Props:    islandProps(mergedProps),
+		}
+		return node
+	}
+}
+
+// islandProps 挑出可序列化為 JSON 的 props 傳給客戶端
+func islandProps(p Props) map[string]any {
+	props := make(map[string]any, len(p))
+	for k, v := range p {
+		switch v.(type) {
+		case JSAction, ServerHandlerRef, VNode, []VNode:
+			continue
+		}
+		if _, err := json.Marshal(v); err != nil {
+			continue
+		}
+		props[k] = v
+	}
+	return props
+}
+
+// islandScript 返回註冊島嶼並啟動 runtime 初始化的腳本
+func islandScript(islands []IslandMeta) string {
+	var sb strings.Builder
+	sb.WriteString("<script>(function(d){")
+	for _, island := range islands {
+		safeScript := strings.ReplaceAll(island.Init, "</script>", "</scr\" + \"ipt>")
+		sb.WriteString("d[" + jsvalue.Encode(island.Name) + "]=" + safeScript + ";")
+	}
+	sb.WriteString("})(window.__gvd.islands);window.__gvd.startIslands();</script>")
+	return sb.String()
+}
@@ -0,0 +1,26 @@
+// query.go
+package dom
+
+import "fmt"
+
+// FindByID 以深度優先順序在樹中尋找 id 屬性等於 id 的節點
+// 找不到時第二個返回值為 false
+//
+// 用法：
+//
+//	if content, ok := FindByID(page, "content"); ok {
+//	    html := Render(content)
+//	}
+func FindByID(v VNode, id string) (VNode, bool) {
+	if v.Tag != "" {
+		if val, ok := v.Props["id"]; ok && fmt.Sprint(val) == id {
+			return v, true
+		}
+	}
+	for _, c := range v.Children {
+		if found, ok := FindByID(c, id); ok {
+			return found, true
+		}
+	}
+	return VNode{}, false
+}
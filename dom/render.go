@@ -4,7 +4,10 @@ package dom
 import (
 	"fmt"
 	"html"
+	"sort"
 	"strings"
+
+	"github.com/TimLai666/go-vdom/internal/jsvalue"
 )
 
 // Render 將虛擬DOM節點轉換為HTML字符串
@@ -12,18 +15,67 @@ import (
 //   - 對屬性值做最小轉義，並在屬性值為字串 "false" 時省略該屬性（便於處理布林屬性表示法）
 //   - 特別處理 `onDOMReady` 屬性：只接受透過 Component 第二參數注入的 JS 函數（建議由 jsdsl.Fn 建立）；該函數會在 DOMContentLoaded 時被呼叫。
 //     注意：不再支援舊的 `onMount` / `onmount` 屬性；所有初始化邏輯必須透過 Component 的第二個參數注入。
+//   - 屬性依名稱排序輸出，相同的 VNode 必定渲染出相同的 HTML（便於快取與比對雜湊）
+//   - Island 組件的初始化代碼每種只輸出一次，集中在 </body> 前（沒有 body 時在結尾）的單一腳本中
 func Render(v VNode) string {
+	return RenderWith(v, RenderOptions{})
+}
+
+// RenderOptions 控制 RenderWith 的輸出
+type RenderOptions struct {
+	// Hydrate 啟用水合標記：由 Component 建立且帶有 onDOMReady 的互動組件會輸出
+	// data-gvd-c（組件類型）與 data-gvd-p（初始化所需的 props，JSON）屬性，
+	// 同一類型的初始化函數只輸出一次，由 runtime 的 __gvd.hydrate() 在既有 DOM 上執行
+	Hydrate bool
+
+	// CollectScripts 收集 onDOMReady 腳本，在 </body> 前（沒有 body 時在結尾）合併為單一腳本輸出：
+	// 同一 Component 的初始化函數只輸出一次，各實例以 props 字面量呼叫；Hydrate 模式優先
+	CollectScripts bool
+}
+
+// RenderWith 依選項將虛擬DOM節點轉換為HTML字符串
+// 用法：html := RenderWith(page, RenderOptions{Hydrate: true})
+func RenderWith(v VNode, opts RenderOptions) string {
+	r := &renderer{opts: opts}
+	r.render(v)
+	r.flushScripts()
+	r.flushIslands()
+	return r.sb.String()
+}
+
+// renderer 保存一次渲染的輸出與狀態
+type renderer struct {
+	sb      strings.Builder
+	opts    RenderOptions
+	defined map[string]bool // 已輸出初始化函數的組件類型（Hydrate 模式）
+
+	islands     []IslandMeta    // 本次渲染用到的島嶼類型，依出現順序
+	islandNames map[string]bool // 已收集的島嶼名稱
+	flushed     int             // 已輸出註冊腳本的島嶼數量
+
+	scriptDefs  []string        // 收集的初始化函數定義（CollectScripts 模式）
+	scriptCalls []string        // 收集的實例呼叫，依出現順序
+	scriptTypes map[string]bool // 已定義的初始化函數鍵
+}
+
+func (r *renderer) render(v VNode) {
 	if v.Tag == "" {
-		return v.Content
+		r.sb.WriteString(v.Content)
+		return
 	}
 
-	var sb strings.Builder
+	sb := &r.sb
 	sb.WriteString("<" + v.Tag)
 
+	// 水合模式下以標記取代內聯的 onDOMReady 腳本
+	meta, hydrate := v.Props[componentMetaKey].(ComponentMeta)
+	hydrate = hydrate && r.opts.Hydrate
+
 	// 收集 onDOMReady（如果有），但不要直接作為屬性輸出
 	var onDOMReady string
 
-	for k, rawVal := range v.Props {
+	for _, k := range sortedKeys(v.Props) {
+		rawVal := v.Props[k]
 		// 當屬性名是 onDOMReady 時，保留其 JS 函數內容以便在 DOMContentLoaded 時呼叫，並跳過將其作為 HTML 屬性輸出
 		// 注意：renderer 僅支援 `onDOMReady`，且該屬性應由 Component 的第二個參數注入（通常由 jsdsl.Fn 產生）。
 		if k == "onDOMReady" {
@@ -114,17 +166,39 @@ func Render(v VNode) string {
 			sb.WriteString(fmt.Sprintf(" %s=\"%s\"", k, escaped))
 		}
 	}
+	if hydrate {
+		sb.WriteString(fmt.Sprintf(" data-gvd-c=\"%s\" data-gvd-p=\"%s\"", html.EscapeString(meta.Type), html.EscapeString(meta.propsJSON())))
+	}
+	if island, ok := v.Props[islandMetaKey].(IslandMeta); ok {
+		sb.WriteString(fmt.Sprintf(" data-gvd-island=\"%s\" data-gvd-load=\"%s\" data-gvd-props=\"%s\"",
+			html.EscapeString(island.Name), html.EscapeString(string(island.Strategy)), html.EscapeString(jsvalue.EncodeJSON(island.Props))))
+		r.addIsland(island)
+	}
 	sb.WriteString(">")
 
 	if v.Content != "" {
 		sb.WriteString(v.Content)
 	}
 	for _, c := range v.Children {
-		sb.WriteString(Render(c))
+		r.render(c)
+	}
+	// 收集的腳本與島嶼註冊腳本放在 body 結尾，此時所有元素都已解析
+	if v.Tag == "body" {
+		r.flushScripts()
+		r.flushIslands()
 	}
 
 	sb.WriteString(fmt.Sprintf("</%s>", v.Tag))
 
+	if hydrate {
+		r.define(meta)
+		return
+	}
+	if r.opts.CollectScripts && onDOMReady != "" {
+		r.collectScript(v.Props, onDOMReady)
+		return
+	}
+
 	// 如果有 onDOMReady，注入對應的 <script>
 	if onDOMReady != "" {
 		// 以簡單方式避免原始 onDOMReady 中出現 "</script>" 導致 HTML 結構中斷
@@ -136,6 +210,148 @@ func Render(v VNode) string {
 		sb.WriteString(onReadyWrapper)
 		sb.WriteString("</script>")
 	}
+}
+
+// collectScript 記錄元素的 onDOMReady；Component 建立的元素共用其類型的初始化函數，
+// 其他元素（如以 props 直接指定 onDOMReady）各自定義
+func (r *renderer) collectScript(p Props, onDOMReady string) {
+	meta, ok := p[componentMetaKey].(ComponentMeta)
+	if !ok {
+		meta = ComponentMeta{Type: fmt.Sprintf("i%d", len(r.scriptCalls)), Init: onDOMReady}
+	}
+	if !r.scriptTypes[meta.Type] {
+		if r.scriptTypes == nil {
+			r.scriptTypes = make(map[string]bool)
+		}
+		r.scriptTypes[meta.Type] = true
+		safeScript := strings.ReplaceAll(meta.Init, "</script>", "</scr\" + \"ipt>")
+		r.scriptDefs = append(r.scriptDefs, "d["+jsvalue.Encode(meta.Type)+"]=function(p){return "+safeScript+";};")
+	}
+	r.scriptCalls = append(r.scriptCalls, "["+jsvalue.Encode(meta.Type)+","+meta.propsJS()+"]")
+}
 
+// flushScripts 輸出收集的腳本：先定義初始化函數，DOM 就緒後依序以各實例的 props 呼叫
+// 各實例分別捕捉例外，避免一個組件的錯誤中斷其他組件的初始化
+func (r *renderer) flushScripts() {
+	if len(r.scriptCalls) == 0 {
+		return
+	}
+	r.sb.WriteString("<script>(function(){var d={};")
+	for _, def := range r.scriptDefs {
+		r.sb.WriteString(def)
+	}
+	r.sb.WriteString("var c=[" + strings.Join(r.scriptCalls, ",") + "];")
+	r.sb.WriteString("var run=function(){c.forEach(function(x){try{d[x[0]](x[1])();}catch(e){console.error(e);}});};")
+	r.sb.WriteString("if(document.readyState==='loading'){document.addEventListener('DOMContentLoaded',run);}else{run();}})();</script>")
+	r.scriptDefs, r.scriptCalls, r.scriptTypes = nil, nil, nil
+}
+
+// addIsland 收集島嶼類型；同名島嶼只保留第一個 init
+func (r *renderer) addIsland(island IslandMeta) {
+	if r.islandNames[island.Name] {
+		return
+	}
+	if r.islandNames == nil {
+		r.islandNames = make(map[string]bool)
+	}
+	r.islandNames[island.Name] = true
+	r.islands = append(r.islands, island)
+}
+
+// flushIslands 輸出尚未註冊的島嶼類型
+func (r *renderer) flushIslands() {
+	if r.flushed == len(r.islands) {
+		return
+	}
+	r.sb.WriteString(islandScript(r.islands[r.flushed:]))
+	r.flushed = len(r.islands)
+}
+
+// define 在組件類型第一次出現時輸出註冊其初始化函數的 <script>
+func (r *renderer) define(meta ComponentMeta) {
+	if r.defined[meta.Type] {
+		return
+	}
+	if r.defined == nil {
+		r.defined = make(map[string]bool)
+	}
+	r.defined[meta.Type] = true
+	safeScript := strings.ReplaceAll(meta.Init, "</script>", "</scr\" + \"ipt>")
+	r.sb.WriteString("<script>window.__gvd.define(" + jsvalue.Encode(meta.Type) + ",function(p){return " + safeScript + ";});</script>")
+}
+
+// sortedKeys 返回依名稱排序的屬性鍵，略過 "gvd:" 開頭的保留鍵
+func sortedKeys(p Props) []string {
+	keys := make([]string, 0, len(p))
+	for k := range p {
+		if !isReservedProp(k) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// xmlEscaper 轉義 XML 文字內容與屬性值中的特殊字元
+var xmlEscaper = strings.NewReplacer(
+	"&", "&amp;",
+	"<", "&lt;",
+	">", "&gt;",
+	"\"", "&quot;",
+	"'", "&apos;",
+)
+
+// RenderXML 以 XML 模式將虛擬DOM節點轉換為字符串
+// 與 Render 的差異：
+//   - 文字內容與屬性值都會做 XML 轉義（Render 會原樣輸出文字內容）
+//   - 沒有內容與子節點的元素輸出為自閉合標籤，例如 <link href="..."/>
+//   - 屬性依名稱排序輸出，相同輸入必定得到相同結果
+//   - 忽略 JSAction、ServerHandlerRef 與 onDOMReady 等只對 HTML 有意義的屬性
+func RenderXML(v VNode) string {
+	var sb strings.Builder
+	renderXML(&sb, v)
 	return sb.String()
 }
+
+// XMLDocument 輸出帶有 XML 宣告的完整 XML 文件
+func XMLDocument(root VNode) string {
+	return `<?xml version="1.0" encoding="UTF-8"?>` + "\n" + RenderXML(root)
+}
+
+func renderXML(sb *strings.Builder, v VNode) {
+	if v.Tag == "" {
+		sb.WriteString(xmlEscaper.Replace(v.Content))
+		return
+	}
+
+	sb.WriteString("<" + v.Tag)
+
+	for _, k := range sortedKeys(v.Props) {
+		if k == "onDOMReady" {
+			continue
+		}
+		var valStr string
+		switch t := v.Props[k].(type) {
+		case nil, JSAction, ServerHandlerRef:
+			continue
+		case string:
+			valStr = t
+		default:
+			valStr = fmt.Sprint(t)
+		}
+		sb.WriteString(fmt.Sprintf(" %s=\"%s\"", k, xmlEscaper.Replace(valStr)))
+	}
+
+	if v.Content == "" && len(v.Children) == 0 {
+		sb.WriteString("/>")
+		return
+	}
+	sb.WriteString(">")
+
+	sb.WriteString(xmlEscaper.Replace(v.Content))
+	for _, c := range v.Children {
+		renderXML(sb, c)
+	}
+
+	sb.WriteString("</" + v.Tag + ">")
+}
@@ -0,0 +1,228 @@
+// render_test.go
+package dom
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRenderWithHydrate(t *testing.T) {
+	init := JSAction{Code: "function(){var el=document.getElementById({{id}});el.dataset.n={{count}};}"}
+	counter := Component(Div(Props{"id": "{{id}}", "class": "counter"}, "{{label}}"), &init, PropsDefault{"count": 0, "label": ""})
+
+	page := Div(nil,
+		counter(Props{"id": "a", "count": 1, "label": "A"}),
+		counter(Props{"id": "b", "count": 2, "label": "B"}),
+	)
+
+	plain := Render(page)
+	if strings.Contains(plain, "data-gvd-c") || strings.Contains(plain, "gvd:") {
+		t.Errorf("default render must not emit hydration markers: %s", plain)
+	}
+	if strings.Count(plain, "<script>") != 2 {
+		t.Errorf("default render should keep one inline script per instance: %s", plain)
+	}
+
+	hydrated := RenderWith(page, RenderOptions{Hydrate: true})
+	typ := compileInit(init.Code).typ
+	wantA := `<div class="counter" id="a" data-gvd-c="` + typ + `" data-gvd-p="{&#34;count&#34;:1,&#34;id&#34;:&#34;a&#34;}">A</div>`
+	if !strings.Contains(hydrated, wantA) {
+		t.Errorf("missing markers for first instance:\n%s\nwant substring\n%s", hydrated, wantA)
+	}
+	if !strings.Contains(hydrated, `data-gvd-p="{&#34;count&#34;:2,&#34;id&#34;:&#34;b&#34;}"`) {
+		t.Errorf("missing props for second instance: %s", hydrated)
+	}
+	if n := strings.Count(hydrated, "<script>"); n != 1 {
+		t.Errorf("init function should be defined once per component type, got %d scripts", n)
+	}
+	wantDefine := `<script>window.__gvd.define("` + typ + `",function(p){return function(){var el=document.getElementById(p["id"]);el.dataset.n=p["count"];};});</script>`
+	if !strings.Contains(hydrated, wantDefine) {
+		t.Errorf("unexpected define script:\n%s\nwant\n%s", hydrated, wantDefine)
+	}
+}
+
+func TestRenderWithHydratePropsAreJSON(t *testing.T) {
+	init := JSAction{Code: "function(){window.v={{v}};window.n={{n}};}"}
+	c := Component(Span(Props{"id": "{{id}}"}), &init, PropsDefault{"v": "", "n": 0.0})
+	out := RenderWith(c(Props{"id": "j1", "v": "</script>'\"", "n": math.NaN()}), RenderOptions{Hydrate: true})
+
+	start := strings.Index(out, `data-gvd-p="`) + len(`data-gvd-p="`)
+	attr := html.UnescapeString(out[start : start+strings.Index(out[start:], `"`)])
+	if !json.Valid([]byte(attr)) {
+		t.Fatalf("data-gvd-p must be strict JSON: %s", attr)
+	}
+	if want := `{"n":{"$gvd":"number","v":"NaN"},"v":"\u003c/script\u003e\u0027\""}`; attr != want {
+		t.Errorf("data-gvd-p = %s, want %s", attr, want)
+	}
+}
+
+func TestComponentMetadataNotInJSON(t *testing.T) {
+	init := JSAction{Code: "function(){}"}
+	c := Component(Div(Props{"id": "{{id}}", "class": "card"}), &init)
+	out, err := ToCompactJSON(c(Props{"id": "m1"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(out, "gvd:") {
+		t.Errorf("reserved props should not be serialized: %s", out)
+	}
+	if !strings.Contains(out, `"class":"card"`) || !strings.Contains(out, `"onDOMReady":{"Code":"function(){}"}`) {
+		t.Errorf("public props should be kept: %s", out)
+	}
+}
+
+func TestRenderWithHydrateExpressionFallback(t *testing.T) {
+	// ${...} 需在伺服器端求值，每個實例各自註冊已插值的代碼
+	init := JSAction{Code: "function(){console.log('${{{on}} ? 'yes' : 'no'}');}"}
+	toggle := Component(Span(Props{"id": "{{id}}"}), &init, PropsDefault{"on": false})
+
+	html := RenderWith(Div(nil, toggle(Props{"id": "t1", "on": true}), toggle(Props{"id": "t2"})), RenderOptions{Hydrate: true})
+	for _, want := range []string{
+		`data-gvd-c="` + compileInit(init.Code).typ + `-t1" data-gvd-p="{}"`,
+		`console.log('yes')`,
+		`console.log('no')`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("missing %q in %s", want, html)
+		}
+	}
+	if n := strings.Count(html, "<script>"); n != 2 {
+		t.Errorf("each instance needs its own definition, got %d scripts", n)
+	}
+}
+
+func TestRenderIslands(t *testing.T) {
+	toggle := Island("toggle",
+		Button(Props{"id": "{{id}}", "type": "button"}, "{{label}}"),
+		JSAction{Code: "(el,props)=>{el.onclick=()=>console.log(props.label);}"},
+		IslandIdle,
+		PropsDefault{"label": "on"},
+	)
+	menu := Island("menu", Nav(nil), JSAction{Code: "(el)=>{}"}, "")
+
+	page := Html(nil, Body(nil,
+		toggle(Props{"id": "t1", "label": "Hi"}),
+		Div(nil, toggle(Props{"id": "t2", "onClick": JSAction{Code: "x()"}})),
+		menu(nil),
+		P(nil, "footer"),
+	))
+	html := Render(page)
+
+	want := `<button id="t1" type="button" data-gvd-island="toggle" data-gvd-load="idle" data-gvd-props="{&#34;id&#34;:&#34;t1&#34;,&#34;label&#34;:&#34;Hi&#34;}">Hi</button>`
+	if !strings.Contains(html, want) {
+		t.Errorf("missing island markers:\n%s\nwant substring\n%s", html, want)
+	}
+	if strings.Contains(html, "onClick") {
+		t.Errorf("JSAction props must not be passed to the island: %s", html)
+	}
+	if !strings.Contains(html, `<nav data-gvd-island="menu" data-gvd-load="load"`) {
+		t.Errorf("empty strategy should default to load: %s", html)
+	}
+
+	script := `<script>(function(d){d["toggle"]=(el,props)=>{el.onclick=()=>console.log(props.label);};d["menu"]=(el)=>{};})(window.__gvd.islands);window.__gvd.startIslands();</script>`
+	if strings.Count(html, "<script>") != 1 || !strings.HasSuffix(html, "<p>footer</p>"+script+"</body></html>") {
+		t.Errorf("islands should be registered once before </body>:\n%s", html)
+	}
+
+	// 沒有 body 的片段在結尾輸出
+	if fragment := Render(Div(nil, menu(nil))); !strings.HasSuffix(fragment, `</div><script>(function(d){d["menu"]=(el)=>{};})(window.__gvd.islands);window.__gvd.startIslands();</script>`) {
+		t.Errorf("fragment should end with the island script: %s", fragment)
+	}
+}
+
+func TestIslandPropsAreJSON(t *testing.T) {
+	clock := Island("clock", Span(Props{"id": "{{id}}"}), JSAction{Code: "(el,props)=>{}"}, IslandLoad)
+	at := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+	node := clock(Props{"id": "c1", "at": at, "raw": []byte{1, 2}})
+
+	out := Render(node)
+	start := strings.Index(out, `data-gvd-props="`) + len(`data-gvd-props="`)
+	attr := html.UnescapeString(out[start : start+strings.Index(out[start:], `"`)])
+	want := `{"at":{"$gvd":"date","v":"2025-05-01T12:00:00Z"},"id":"c1","raw":{"$gvd":"bytes","v":[1,2]}}`
+	if attr != want || !json.Valid([]byte(attr)) {
+		t.Errorf("data-gvd-props = %s, want JSON %s", attr, want)
+	}
+
+	if data, err := ToCompactJSON(node); err != nil || strings.Contains(data, "gvd:island") {
+		t.Errorf("island metadata should not be serialized: %s (%v)", data, err)
+	}
+}
+
+func TestRenderWithCollectScripts(t *testing.T) {
+	init := JSAction{Code: "function(){document.getElementById({{id}}).checked={{checked}};}"}
+	checkbox := Component(Input(Props{"id": "{{id}}", "type": "checkbox"}), &init, PropsDefault{"checked": false})
+
+	var items []VNode
+	for i := 0; i < 50; i++ {
+		items = append(items, checkbox(Props{"id": fmt.Sprintf("cb%d", i), "checked": i%2 == 0}))
+	}
+	custom := Div(Props{"onDOMReady": JSAction{Code: "function(){window.ready=true;}"}})
+	page := Html(nil, Body(nil, Ul(nil, items), custom, P(nil, "end")))
+
+	plain := Render(page)
+	collected := RenderWith(page, RenderOptions{CollectScripts: true})
+
+	if n := strings.Count(plain, "<script>"); n != 51 {
+		t.Fatalf("default render: %d scripts, want 51", n)
+	}
+	if n := strings.Count(collected, "<script>"); n != 1 {
+		t.Fatalf("collected render: %d scripts, want 1", n)
+	}
+	if strings.Count(collected, "getElementById(p[\"id\"])") != 1 {
+		t.Errorf("shared init should be emitted once: %s", collected)
+	}
+	typ := compileInit(init.Code).typ
+	for _, want := range []string{
+		`["` + typ + `",{"checked":true,"id":"cb0"}]`,
+		`["` + typ + `",{"checked":false,"id":"cb49"}]`,
+		`d["i50"]=function(p){return function(){window.ready=true;};};`,
+		`["i50",{}]`,
+	} {
+		if !strings.Contains(collected, want) {
+			t.Errorf("missing %s", want)
+		}
+	}
+	if !strings.HasSuffix(collected, "})();</script></body></html>") || !strings.Contains(collected, "<p>end</p><script>") {
+		t.Errorf("collected script should be the last element of <body>: %s", collected[len(collected)-200:])
+	}
+	if len(collected) >= len(plain)/2 {
+		t.Errorf("collected output (%d bytes) should be much smaller than inline output (%d bytes)", len(collected), len(plain))
+	}
+}
+
+func TestComponentJSEmbeddingIsConsistent(t *testing.T) {
+	// 內聯與收集模式的 props 以 jsvalue 轉為相同的 JavaScript 字面量；水合模式輸出 JSON，Date 以標記物件表示
+	init := JSAction{Code: "function(){window.at={{at}};}"}
+	stamp := Component(Span(Props{"id": "{{id}}"}), &init, PropsDefault{"at": nil})
+	at := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+	page := Html(nil, Body(nil, stamp(Props{"id": "s1", "at": at})))
+	date := `new Date("2025-05-01T12:00:00Z")`
+
+	if inline := Render(page); !strings.Contains(inline, "window.at="+date) {
+		t.Errorf("inline render should embed a Date: %s", inline)
+	}
+	if hydrated := RenderWith(page, RenderOptions{Hydrate: true}); !strings.Contains(hydrated, `data-gvd-p="`+html.EscapeString(`{"at":{"$gvd":"date","v":"2025-05-01T12:00:00Z"}}`)+`"`) {
+		t.Errorf("hydrated props should be JSON with a tagged Date: %s", hydrated)
+	}
+	if collected := RenderWith(page, RenderOptions{CollectScripts: true}); !strings.Contains(collected, `{"at":`+date+`}`) {
+		t.Errorf("collected props should embed a Date: %s", collected)
+	}
+}
+
+func TestComponentExpressionUsesJSValue(t *testing.T) {
+	// ${...} 中的 {{key}} 與裸 {{key}} 使用相同的轉義
+	init := JSAction{Code: "function(){window.v=${{{name}} === 'x' ? 'a' : {{name}}};}"}
+	c := Component(Span(Props{"id": "{{id}}"}), &init, PropsDefault{"name": ""})
+	out := Render(c(Props{"id": "e1", "name": "</script>'"}))
+	if strings.Contains(out, "</script>'") {
+		t.Fatalf("value must be escaped inside ${...}: %s", out)
+	}
+	if !strings.Contains(out, `window.v=\u003c/script\u003e\u0027;`) {
+		t.Errorf("unexpected expression output: %s", out)
+	}
+}
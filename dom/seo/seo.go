@@ -0,0 +1,337 @@
+// seo.go
+package seo
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/TimLai666/go-vdom/dom"
+)
+
+// OpenGraph 定義 OpenGraph（og:*）標籤的內容
+// 空字串欄位不會輸出對應的 meta 標籤
+type OpenGraph struct {
+	Title       string // og:title
+	Description string // og:description
+	URL         string // og:url
+	Image       string // og:image
+	ImageAlt    string // og:image:alt
+	Type        string // og:type，如 "website"、"article"、"product"，預設 "website"
+	SiteName    string // og:site_name
+	Locale      string // og:locale，如 "zh_TW"
+}
+
+// Props 返回 OpenGraph 的 meta 屬性列表，可直接傳給 Document 的 metas 參數
+func (og OpenGraph) Props() []dom.Props {
+	ogType := og.Type
+	if ogType == "" {
+		ogType = "website"
+	}
+
+	var metas []dom.Props
+	metas = appendProperty(metas, "og:title", og.Title)
+	metas = appendProperty(metas, "og:description", og.Description)
+	metas = appendProperty(metas, "og:url", og.URL)
+	metas = appendProperty(metas, "og:type", ogType)
+	metas = appendProperty(metas, "og:image", og.Image)
+	metas = appendProperty(metas, "og:image:alt", og.ImageAlt)
+	metas = appendProperty(metas, "og:site_name", og.SiteName)
+	metas = appendProperty(metas, "og:locale", og.Locale)
+	return metas
+}
+
+// Nodes 返回 OpenGraph 的 <meta> 節點
+func (og OpenGraph) Nodes() []dom.VNode {
+	return metaNodes(og.Props())
+}
+
+// TwitterCard 定義 Twitter（X）卡片標籤的內容
+type TwitterCard struct {
+	Card        string // twitter:card，如 "summary"、"summary_large_image"，預設 "summary"
+	Site        string // twitter:site，網站帳號，如 "@golang"
+	Creator     string // twitter:creator，作者帳號
+	Title       string // twitter:title
+	Description string // twitter:description
+	Image       string // twitter:image
+	ImageAlt    string // twitter:image:alt
+}
+
+// Props 返回 Twitter 卡片的 meta 屬性列表，可直接傳給 Document 的 metas 參數
+func (tc TwitterCard) Props() []dom.Props {
+	card := tc.Card
+	if card == "" {
+		card = "summary"
+	}
+
+	var metas []dom.Props
+	metas = appendName(metas, "twitter:card", card)
+	metas = appendName(metas, "twitter:site", tc.Site)
+	metas = appendName(metas, "twitter:creator", tc.Creator)
+	metas = appendName(metas, "twitter:title", tc.Title)
+	metas = appendName(metas, "twitter:description", tc.Description)
+	metas = appendName(metas, "twitter:image", tc.Image)
+	metas = appendName(metas, "twitter:image:alt", tc.ImageAlt)
+	return metas
+}
+
+// Nodes 返回 Twitter 卡片的 <meta> 節點
+func (tc TwitterCard) Nodes() []dom.VNode {
+	return metaNodes(tc.Props())
+}
+
+// Canonical 創建 <link rel="canonical"> 節點
+func Canonical(url string) dom.VNode {
+	return dom.Link(dom.Props{"rel": "canonical", "href": url})
+}
+
+// CanonicalLink 返回 canonical 鏈接的 LinkInfo，可直接傳給 Document 的 links 參數
+func CanonicalLink(url string) dom.LinkInfo {
+	return dom.LinkInfo{Rel: "canonical", Href: url}
+}
+
+// Person 對應 schema.org 的 Person
+type Person struct {
+	Name string
+	URL  string
+}
+
+// Organization 對應 schema.org 的 Organization
+type Organization struct {
+	Name   string
+	URL    string
+	Logo   string
+	SameAs []string // 社群帳號等其他代表此組織的網址
+}
+
+// Article 對應 schema.org 的 Article
+type Article struct {
+	Headline      string
+	Description   string
+	URL           string
+	Images        []string
+	Authors       []Person
+	Publisher     *Organization
+	DatePublished time.Time
+	DateModified  time.Time
+}
+
+// Offer 對應 schema.org 的 Offer
+type Offer struct {
+	Price         string // 價格，使用字串避免浮點誤差，如 "199.00"
+	PriceCurrency string // ISO 4217 幣別，如 "TWD"
+	Availability  string // 如 "InStock"、"OutOfStock"，會自動補上 https://schema.org/ 前綴
+	URL           string
+}
+
+// Product 對應 schema.org 的 Product
+type Product struct {
+	Name        string
+	Description string
+	SKU         string
+	Brand       string
+	Images      []string
+	Offers      []Offer
+}
+
+// BreadcrumbItem 是麵包屑中的一個層級
+type BreadcrumbItem struct {
+	Name string
+	URL  string
+}
+
+// Breadcrumb 對應 schema.org 的 BreadcrumbList
+// Items 依序為由根到當前頁面的層級
+type Breadcrumb struct {
+	Items []BreadcrumbItem
+}
+
+// JSONLD 返回 Article 的 JSON-LD <script> 節點
+func (a Article) JSONLD() dom.VNode {
+	return JSONLD(a.schema())
+}
+
+// JSONLD 返回 Product 的 JSON-LD <script> 節點
+func (p Product) JSONLD() dom.VNode {
+	return JSONLD(p.schema())
+}
+
+// JSONLD 返回 Organization 的 JSON-LD <script> 節點
+func (o Organization) JSONLD() dom.VNode {
+	return JSONLD(o.schema(true))
+}
+
+// JSONLD 返回 Breadcrumb 的 JSON-LD <script> 節點
+func (b Breadcrumb) JSONLD() dom.VNode {
+	return JSONLD(b.schema())
+}
+
+// JSONLD 將任意值序列化為 <script type="application/ld+json"> 節點
+// 輸出會轉義 <、>、&、U+2028 與 U+2029，因此內容無法提前結束 <script> 區塊
+// 若 v 無法序列化，返回空的文字節點
+func JSONLD(v any) dom.VNode {
+	data, err := marshalScriptSafe(v)
+	if err != nil {
+		return dom.Text("")
+	}
+	return dom.Script(dom.Props{"type": "application/ld+json"}, string(data))
+}
+
+// marshalScriptSafe 將值序列化為可安全放入 <script> 的 JSON
+// encoding/json 預設會轉義 <、>、& 與 U+2028/U+2029，這裡明確依賴此行為
+func marshalScriptSafe(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(true)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimRight(buf.Bytes(), "\n"), nil
+}
+
+// schemaObject 是一個 schema.org 物件（encoding/json 會依鍵排序輸出，結果穩定）
+type schemaObject map[string]any
+
+func newSchema(schemaType string, withContext bool) schemaObject {
+	obj := schemaObject{"@type": schemaType}
+	if withContext {
+		obj["@context"] = "https://schema.org"
+	}
+	return obj
+}
+
+// set 只在值非空時寫入欄位
+func (o schemaObject) set(key string, val any) {
+	switch t := val.(type) {
+	case string:
+		if t == "" {
+			return
+		}
+	case []string:
+		if len(t) == 0 {
+			return
+		}
+	case []any:
+		if len(t) == 0 {
+			return
+		}
+	case time.Time:
+		if t.IsZero() {
+			return
+		}
+		val = t.Format(time.RFC3339)
+	case nil:
+		return
+	}
+	o[key] = val
+}
+
+func (a Article) schema() schemaObject {
+	obj := newSchema("Article", true)
+	obj.set("headline", a.Headline)
+	obj.set("description", a.Description)
+	obj.set("url", a.URL)
+	obj.set("image", a.Images)
+	if len(a.Authors) > 0 {
+		authors := make([]any, 0, len(a.Authors))
+		for _, p := range a.Authors {
+			authors = append(authors, p.schema())
+		}
+		obj.set("author", authors)
+	}
+	if a.Publisher != nil {
+		obj.set("publisher", a.Publisher.schema(false))
+	}
+	obj.set("datePublished", a.DatePublished)
+	obj.set("dateModified", a.DateModified)
+	return obj
+}
+
+func (p Person) schema() schemaObject {
+	obj := newSchema("Person", false)
+	obj.set("name", p.Name)
+	obj.set("url", p.URL)
+	return obj
+}
+
+func (o Organization) schema(withContext bool) schemaObject {
+	obj := newSchema("Organization", withContext)
+	obj.set("name", o.Name)
+	obj.set("url", o.URL)
+	obj.set("logo", o.Logo)
+	obj.set("sameAs", o.SameAs)
+	return obj
+}
+
+func (p Product) schema() schemaObject {
+	obj := newSchema("Product", true)
+	obj.set("name", p.Name)
+	obj.set("description", p.Description)
+	obj.set("sku", p.SKU)
+	obj.set("image", p.Images)
+	if p.Brand != "" {
+		brand := newSchema("Brand", false)
+		brand.set("name", p.Brand)
+		obj.set("brand", brand)
+	}
+	if len(p.Offers) > 0 {
+		offers := make([]any, 0, len(p.Offers))
+		for _, offer := range p.Offers {
+			offers = append(offers, offer.schema())
+		}
+		obj.set("offers", offers)
+	}
+	return obj
+}
+
+func (o Offer) schema() schemaObject {
+	obj := newSchema("Offer", false)
+	obj.set("price", o.Price)
+	obj.set("priceCurrency", o.PriceCurrency)
+	availability := o.Availability
+	if availability != "" && !strings.HasPrefix(availability, "http") {
+		availability = "https://schema.org/" + availability
+	}
+	obj.set("availability", availability)
+	obj.set("url", o.URL)
+	return obj
+}
+
+func (b Breadcrumb) schema() schemaObject {
+	obj := newSchema("BreadcrumbList", true)
+	items := make([]any, 0, len(b.Items))
+	for i, item := range b.Items {
+		entry := newSchema("ListItem", false)
+		entry["position"] = i + 1
+		entry.set("name", item.Name)
+		entry.set("item", item.URL)
+		items = append(items, entry)
+	}
+	obj["itemListElement"] = items
+	return obj
+}
+
+// appendProperty 添加以 property 屬性標示的 meta（OpenGraph 使用）
+func appendProperty(metas []dom.Props, property, content string) []dom.Props {
+	if content == "" {
+		return metas
+	}
+	return append(metas, dom.Props{"property": property, "content": content})
+}
+
+// appendName 添加以 name 屬性標示的 meta（Twitter 使用）
+func appendName(metas []dom.Props, name, content string) []dom.Props {
+	if content == "" {
+		return metas
+	}
+	return append(metas, dom.Props{"name": name, "content": content})
+}
+
+func metaNodes(metas []dom.Props) []dom.VNode {
+	nodes := make([]dom.VNode, len(metas))
+	for i, p := range metas {
+		nodes[i] = dom.Meta(p)
+	}
+	return nodes
+}
@@ -0,0 +1,159 @@
+package seo
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TimLai666/go-vdom/dom"
+)
+
+func TestOpenGraphProps(t *testing.T) {
+	og := OpenGraph{
+		Title:    "Go VDOM",
+		URL:      "https://example.com/",
+		Image:    "https://example.com/og.png",
+		SiteName: "Example",
+	}
+
+	metas := og.Props()
+	want := map[string]string{
+		"og:title":     "Go VDOM",
+		"og:url":       "https://example.com/",
+		"og:type":      "website",
+		"og:image":     "https://example.com/og.png",
+		"og:site_name": "Example",
+	}
+	if len(metas) != len(want) {
+		t.Fatalf("got %d metas, want %d: %v", len(metas), len(want), metas)
+	}
+	for _, m := range metas {
+		prop, _ := m["property"].(string)
+		if want[prop] != m["content"] {
+			t.Errorf("%s = %v, want %q", prop, m["content"], want[prop])
+		}
+	}
+
+	html := dom.Render(og.Nodes()[0])
+	if !strings.Contains(html, `property="og:title"`) || !strings.Contains(html, `content="Go VDOM"`) {
+		t.Errorf("unexpected meta node: %s", html)
+	}
+}
+
+func TestTwitterCardProps(t *testing.T) {
+	metas := TwitterCard{Card: "summary_large_image", Site: "@golang", Title: "Hi"}.Props()
+	if len(metas) != 3 {
+		t.Fatalf("got %d metas, want 3: %v", len(metas), metas)
+	}
+	if metas[0]["name"] != "twitter:card" || metas[0]["content"] != "summary_large_image" {
+		t.Errorf("first meta = %v, want twitter:card", metas[0])
+	}
+
+	defaults := TwitterCard{}.Props()
+	if len(defaults) != 1 || defaults[0]["content"] != "summary" {
+		t.Errorf("empty card should default to summary, got %v", defaults)
+	}
+}
+
+func TestCanonical(t *testing.T) {
+	html := dom.Render(Canonical("https://example.com/a?b=1&c=2"))
+	if !strings.Contains(html, `rel="canonical"`) || !strings.Contains(html, `href="https://example.com/a?b=1&amp;c=2"`) {
+		t.Errorf("unexpected canonical link: %s", html)
+	}
+	if link := CanonicalLink("https://example.com/"); link.Rel != "canonical" {
+		t.Errorf("CanonicalLink rel = %q", link.Rel)
+	}
+}
+
+func TestArticleJSONLD(t *testing.T) {
+	published := time.Date(2025, 3, 1, 8, 0, 0, 0, time.UTC)
+	node := Article{
+		Headline:      "Hello",
+		Authors:       []Person{{Name: "Tim"}},
+		Publisher:     &Organization{Name: "Example", Logo: "https://example.com/logo.png"},
+		DatePublished: published,
+	}.JSONLD()
+
+	if node.Tag != "script" || node.Props["type"] != "application/ld+json" {
+		t.Fatalf("unexpected node: %+v", node)
+	}
+
+	var data map[string]any
+	if err := json.Unmarshal([]byte(node.Content), &data); err != nil {
+		t.Fatalf("invalid JSON-LD: %v\n%s", err, node.Content)
+	}
+	if data["@context"] != "https://schema.org" || data["@type"] != "Article" {
+		t.Errorf("missing schema type: %v", data)
+	}
+	if data["datePublished"] != "2025-03-01T08:00:00Z" {
+		t.Errorf("datePublished = %v", data["datePublished"])
+	}
+	if _, ok := data["dateModified"]; ok {
+		t.Error("zero dateModified should be omitted")
+	}
+	publisher := data["publisher"].(map[string]any)
+	if _, ok := publisher["@context"]; ok {
+		t.Error("nested objects should not repeat @context")
+	}
+}
+
+func TestProductJSONLD(t *testing.T) {
+	node := Product{
+		Name:   "Gopher",
+		Brand:  "Go",
+		Offers: []Offer{{Price: "199.00", PriceCurrency: "TWD", Availability: "InStock"}},
+	}.JSONLD()
+
+	var data map[string]any
+	if err := json.Unmarshal([]byte(node.Content), &data); err != nil {
+		t.Fatalf("invalid JSON-LD: %v", err)
+	}
+	offer := data["offers"].([]any)[0].(map[string]any)
+	if offer["availability"] != "https://schema.org/InStock" {
+		t.Errorf("availability = %v", offer["availability"])
+	}
+	if data["brand"].(map[string]any)["name"] != "Go" {
+		t.Errorf("brand = %v", data["brand"])
+	}
+}
+
+func TestBreadcrumbJSONLD(t *testing.T) {
+	node := Breadcrumb{Items: []BreadcrumbItem{
+		{Name: "首頁", URL: "https://example.com/"},
+		{Name: "部落格", URL: "https://example.com/blog"},
+	}}.JSONLD()
+
+	var data map[string]any
+	if err := json.Unmarshal([]byte(node.Content), &data); err != nil {
+		t.Fatalf("invalid JSON-LD: %v", err)
+	}
+	items := data["itemListElement"].([]any)
+	if len(items) != 2 {
+		t.Fatalf("got %d items, want 2", len(items))
+	}
+	second := items[1].(map[string]any)
+	if second["position"] != float64(2) || second["name"] != "部落格" {
+		t.Errorf("second item = %v", second)
+	}
+}
+
+func TestJSONLDEscapesScriptContext(t *testing.T) {
+	node := Organization{Name: "</script><script>alert(1)</script>", URL: "a\u2028b<!--"}.JSONLD()
+	html := dom.Render(node)
+
+	body := strings.TrimSuffix(strings.SplitN(html, ">", 2)[1], "</script>")
+	for _, bad := range []string{"</script", "<!--", "\u2028"} {
+		if strings.Contains(body, bad) {
+			t.Errorf("JSON-LD body contains %q: %s", bad, body)
+		}
+	}
+
+	var data map[string]any
+	if err := json.Unmarshal([]byte(node.Content), &data); err != nil {
+		t.Fatalf("escaped JSON should still be valid: %v", err)
+	}
+	if data["name"] != "</script><script>alert(1)</script>" {
+		t.Errorf("name did not round-trip: %v", data["name"])
+	}
+}
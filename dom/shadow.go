@@ -0,0 +1,110 @@
+// shadow.go
+package dom
+
+import "strings"
+
+// shadowHostTags 是可直接以 span 作為宿主的行內根元素；其餘組件以 div 作為宿主
+var shadowHostTags = map[string]bool{
+	"span": true, "a": true, "button": true, "label": true, "input": true,
+	"select": true, "textarea": true, "img": true, "code": true,
+}
+
+// ShadowComponent 與 Component 相同，但組件在 Declarative Shadow DOM 中渲染，
+// 不受頁面全域 CSS（如 Bootstrap）影響，組件內的樣式也不會外洩：
+//
+//		<div id="…" data-gvd-shadow style="display:contents">
+//		  <template shadowrootmode="open"><style>css</style>模板</template>
+//		  children…
+//		</div>
+//
+//	  - css: 只作用於組件內部的樣式表，可留空；同樣支援 {{key}} 插值
+//	  - 宿主元素帶有組件的 id，頁面上的 document.getElementById(id) 取得宿主；
+//	    模板根元素在 shadow root 中保留相同的 id
+//	  - 模板中的 {{children}} 成為 <slot>，children 保留在宿主的 light DOM 中，可被頁面 CSS 設定樣式
+//	  - onDOMReady 與模板中內聯事件處理器的 document.getElementById / querySelector / querySelectorAll
+//	    改為在組件的 shadow root 中查找
+//
+// 需要支援 Declarative Shadow DOM 的瀏覽器（Chrome 111、Safari 16.4、Firefox 123 起）
+//
+// 用法：
+//
+//	var ScopedCard = ShadowComponent(cardTemplate, `h3 { margin: 0; }`, &cardInit, PropsDefault{"title": ""})
+func ShadowComponent(template VNode, css string, onDOMReadyCallback *JSAction, defaultProps ...PropsDefault) func(props Props, children ...VNode) VNode {
+	spec := &ComponentSpec{Template: template}
+	if onDOMReadyCallback != nil {
+		spec.OnDOMReady = onDOMReadyCallback.Code
+	}
+	if len(defaultProps) > 0 {
+		spec.Defaults = defaultProps[0]
+	}
+
+	var init *JSAction
+	if onDOMReadyCallback != nil && strings.TrimSpace(onDOMReadyCallback.Code) != "" {
+		// 初始化時先取得宿主的 shadow root，代碼中的查找都在其中進行
+		init = &JSAction{Code: "function(){var host=document.getElementById({{id}});var root=host&&host.shadowRoot;if(!root)return;return (" +
+			ShadowLookup(onDOMReadyCallback.Code, "root") + ")();}"}
+	}
+	inner := Component(shadowTemplate(template), init, defaultProps...)
+
+	hostTag := "div"
+	if shadowHostTags[template.Tag] {
+		hostTag = "span"
+	}
+
+	return func(p Props, children ...VNode) VNode {
+		mergedProps := mergeComponentProps(defaultProps, p)
+		root := inner(mergedProps)
+		id := mergedProps["id"]
+
+		host := VNode{
+			Tag:   hostTag,
+			Props: Props{"id": id, "data-gvd-shadow": true, "style": "display:contents", componentSpecKey: spec},
+		}
+		// onDOMReady 與水合資訊移到宿主上，腳本與標記輸出在 light DOM 中
+		for _, k := range []string{"onDOMReady", componentMetaKey} {
+			if v, ok := root.Props[k]; ok {
+				host.Props[k] = v
+				delete(root.Props, k)
+			}
+		}
+		delete(root.Props, componentSpecKey)
+
+		shadow := VNode{Tag: "template", Props: Props{"shadowrootmode": "open"}}
+		if css != "" {
+			shadow.Children = append(shadow.Children, VNode{Tag: "style", Content: interpolateString(css, mergedProps)})
+		}
+		shadow.Children = append(shadow.Children, root)
+		host.Children = append([]VNode{shadow}, children...)
+		return host
+	}
+}
+
+// shadowTemplate 返回在 shadow root 中使用的模板：{{children}} 成為 <slot>，
+// 內聯事件處理器中的 document 查找改為在元素所屬的 shadow root 中進行
+func shadowTemplate(v VNode) VNode {
+	if v.Tag == "" {
+		if strings.TrimSpace(v.Content) == "{{children}}" {
+			return VNode{Tag: "slot"}
+		}
+		return v
+	}
+	out := VNode{Tag: v.Tag, Content: v.Content}
+	if v.Props != nil {
+		out.Props = make(Props, len(v.Props))
+		for k, val := range v.Props {
+			if len(k) > 2 && strings.HasPrefix(k, "on") && k != "onDOMReady" {
+				switch t := val.(type) {
+				case JSAction:
+					val = JSAction{Code: ShadowLookup(t.Code, "this.getRootNode()")}
+				case string:
+					val = ShadowLookup(t, "this.getRootNode()")
+				}
+			}
+			out.Props[k] = val
+		}
+	}
+	for _, c := range v.Children {
+		out.Children = append(out.Children, shadowTemplate(c))
+	}
+	return out
+}
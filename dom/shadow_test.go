@@ -0,0 +1,67 @@
+// shadow_test.go
+package dom
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestShadowComponent(t *testing.T) {
+	init := JSAction{Code: "function(){document.getElementById({{id}}).dataset.ready=true;}"}
+	card := ShadowComponent(
+		Div(Props{"id": "{{id}}", "class": "card"},
+			H3(Props{"onClick": JSAction{Code: "document.querySelector('.body').hidden=true"}}, "{{title}}"),
+			Div(Props{"class": "body"}, "{{children}}"),
+		),
+		"h3 { color: {{color}}; }",
+		&init,
+		PropsDefault{"title": "", "color": "black"},
+	)
+
+	node := card(Props{"id": "c1", "title": "Hi"}, P(nil, "content"))
+	if got, ok := FindByID(node, "c1"); !ok || got.Tag != "div" || got.Props["data-gvd-shadow"] != true {
+		t.Errorf("host should carry the component id: %+v", got)
+	}
+	if _, ok := SpecOf(node); !ok {
+		t.Error("host should keep the component spec")
+	}
+	if data, err := ToJSON(node); err != nil || strings.Contains(data, "gvd:") {
+		t.Errorf("component spec and metadata should not be serialized: %s (%v)", data, err)
+	}
+
+	html := Render(node)
+	want := `<div data-gvd-shadow id="c1" style="display:contents"><template shadowrootmode="open"><style>h3 { color: black; }</style>` +
+		`<div class="card" id="c1"><h3 onClick="this.getRootNode().querySelector('.body').hidden=true">Hi</h3><div class="body"><slot></slot></div></div>` +
+		`</template><p>content</p></div><script>`
+	if !strings.HasPrefix(html, want) {
+		t.Errorf("unexpected shadow markup:\n%s\nwant prefix\n%s", html, want)
+	}
+	if !strings.Contains(html, `var host=document.getElementById("c1");var root=host&&host.shadowRoot;if(!root)return;return (function(){root.getElementById("c1").dataset.ready=true;})();`) {
+		t.Errorf("onDOMReady should look up elements in the shadow root: %s", html)
+	}
+	if strings.Count(html, "<script>") != 1 || !strings.HasSuffix(html, "</script>") {
+		t.Errorf("the init script belongs after the host in light DOM: %s", html)
+	}
+
+	hydrated := RenderWith(node, RenderOptions{Hydrate: true})
+	if !strings.Contains(hydrated, `<div data-gvd-shadow id="c1" style="display:contents" data-gvd-c="`) {
+		t.Errorf("hydration markers belong on the host: %s", hydrated)
+	}
+}
+
+func TestShadowComponentInlineHost(t *testing.T) {
+	badge := ShadowComponent(Span(Props{"class": "badge"}, "{{label}}"), "", nil)
+	html := Render(badge(Props{"id": "b", "label": "new"}))
+	want := `<span data-gvd-shadow id="b" style="display:contents"><template shadowrootmode="open"><span class="badge">new</span></template></span>`
+	if html != want {
+		t.Errorf("Render = %s\nwant %s", html, want)
+	}
+}
+
+func TestShadowLookup(t *testing.T) {
+	code := "document.getElementById('a');document.querySelector('.b');document.querySelectorAll('li');document.title"
+	want := "root.getElementById('a');root.querySelector('.b');root.querySelectorAll('li');document.title"
+	if got := ShadowLookup(code, "root"); got != want {
+		t.Errorf("ShadowLookup =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -2,6 +2,7 @@
 package dom
 
 import (
+	"os"
 	"strings"
 
 	"github.com/TimLai666/go-vdom/runtime"
@@ -74,6 +75,10 @@ func tag(name string, props Props, children ...any) VNode {
 	}
 }
 
+// Element 創建任意名稱的元素節點，用於沒有對應函數的標籤或 XML 文件（如 sitemap、RSS）
+// 用法：Element("urlset", Props{"xmlns": "..."}, Element("url", nil, ...))
+func Element(name string, p Props, children ...any) VNode { return tag(name, p, children...) }
+
 // Title 創建 <title> 標籤
 func Title(s string) VNode { return tag("title", nil, Text(s)) }
 
@@ -131,6 +136,11 @@ func Document(title string, links []LinkInfo, scripts []ScriptInfo, metas []Prop
 	// 自動注入 go-vdom runtime 腳本（必須在其他腳本之前加載）
 	headElements = append(headElements, Script(Props{}, runtime.ClientRuntime()))
 
+	// 開發模式（由 gvd dev 設定環境變數）下注入自動重新整理腳本
+	if endpoint := os.Getenv(runtime.DevReloadEnv); endpoint != "" {
+		headElements = append(headElements, Script(Props{}, runtime.DevReloadScript(endpoint)))
+	}
+
 	// 添加腳本
 	for _, script := range scripts {
 		props := Props{"src": script.Src}
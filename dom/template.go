@@ -43,6 +43,9 @@ func renderToGoTemplate(sb *strings.Builder, v VNode, depth int) {
 
 	// 渲染屬性
 	for k, val := range v.Props {
+		if isReservedProp(k) {
+			continue
+		}
 		sb.WriteString(" ")
 		sb.WriteString(k)
 		sb.WriteString("=\"")
@@ -1,12 +1,28 @@
 // types.go
 package dom
 
+import "encoding/json"
+
 // Props 是一個用於存儲元素屬性的映射
 // 調整：Props 現在允許儲存多種型別（例如 string、JSAction、ServerHandlerRef 等）
 // 這使得你可以在 Props 中直接傳入 JSAction（代表要在 client 端執行的 JS 片段）
 // 或其他未來擴展用的引用型別。
 type Props map[string]any
 
+// MarshalJSON 略過以 "gvd:" 開頭的保留鍵：組件與島嶼的內部資訊只供渲染使用，不出現在 ToJSON 等輸出中
+func (p Props) MarshalJSON() ([]byte, error) {
+	if p == nil {
+		return []byte("null"), nil
+	}
+	public := make(map[string]any, len(p))
+	for k, v := range p {
+		if !isReservedProp(k) {
+			public[k] = v
+		}
+	}
+	return json.Marshal(public)
+}
+
 // VNode 表示虛擬DOM中的一個節點
 type VNode struct {
 	Tag      string
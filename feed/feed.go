@@ -0,0 +1,284 @@
+// feed.go
+package feed
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	. "github.com/TimLai666/go-vdom/dom"
+)
+
+// AtomNamespace 是 Atom 1.0 的 XML 命名空間
+const AtomNamespace = "http://www.w3.org/2005/Atom"
+
+// contentNamespace 是 RSS content:encoded 擴充的命名空間
+const contentNamespace = "http://purl.org/rss/1.0/modules/content/"
+
+// Author 是 feed 或項目的作者
+type Author struct {
+	Name  string
+	Email string
+	URI   string
+}
+
+// Item 是 feed 中的一個項目（RSS 的 <item>、Atom 的 <entry>）
+type Item struct {
+	ID         string // 唯一識別碼；Atom 必填，空字串時使用 Link
+	Title      string
+	Link       string
+	Summary    string // 純文字摘要
+	Content    VNode  // 完整內容，會以 Render 渲染為 HTML 後轉義輸出
+	Author     *Author
+	Categories []string
+	Published  time.Time
+	Updated    time.Time // 零值時使用 Published
+}
+
+// Feed 描述一個可同時輸出為 RSS 2.0 與 Atom 1.0 的 feed
+type Feed struct {
+	ID          string // Atom 必填，空字串時使用 Link
+	Title       string
+	Link        string // 網站首頁網址
+	FeedURL     string // feed 本身的網址（rel="self"）
+	Description string // RSS 必填
+	Language    string
+	Author      *Author
+	Updated     time.Time // 零值時使用最新項目的更新時間
+	Items       []Item
+}
+
+// ValidateRSS 檢查 feed 是否包含 RSS 2.0 規範要求的欄位
+func (f Feed) ValidateRSS() error {
+	var errs []error
+	if f.Title == "" {
+		errs = append(errs, errors.New("rss: channel title is required"))
+	}
+	if f.Link == "" {
+		errs = append(errs, errors.New("rss: channel link is required"))
+	}
+	if f.Description == "" {
+		errs = append(errs, errors.New("rss: channel description is required"))
+	}
+	for i, it := range f.Items {
+		if it.Title == "" && it.Summary == "" && !hasContent(it.Content) {
+			errs = append(errs, fmt.Errorf("rss: item[%d]: title or description is required", i))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ValidateAtom 檢查 feed 是否包含 Atom 1.0（RFC 4287）要求的欄位
+func (f Feed) ValidateAtom() error {
+	var errs []error
+	if id := f.atomID(); id == "" {
+		errs = append(errs, errors.New("atom: feed id is required"))
+	} else if !isIRI(id) {
+		errs = append(errs, fmt.Errorf("atom: feed id %q must be an absolute IRI", id))
+	}
+	if f.Title == "" {
+		errs = append(errs, errors.New("atom: feed title is required"))
+	}
+	if f.updated().IsZero() {
+		errs = append(errs, errors.New("atom: feed updated is required"))
+	}
+
+	for i, it := range f.Items {
+		id := it.atomID()
+		if id == "" {
+			errs = append(errs, fmt.Errorf("atom: entry[%d]: id is required", i))
+		} else if !isIRI(id) {
+			errs = append(errs, fmt.Errorf("atom: entry[%d]: id %q must be an absolute IRI", i, id))
+		}
+		if it.Title == "" {
+			errs = append(errs, fmt.Errorf("atom: entry[%d]: title is required", i))
+		}
+		if it.updated().IsZero() {
+			errs = append(errs, fmt.Errorf("atom: entry[%d]: updated is required", i))
+		}
+		if it.Author == nil && f.Author == nil {
+			errs = append(errs, fmt.Errorf("atom: entry[%d]: author is required when the feed has none", i))
+		}
+		if it.Link == "" && !hasContent(it.Content) {
+			errs = append(errs, fmt.Errorf("atom: entry[%d]: content or link is required", i))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// RSS 驗證並輸出 RSS 2.0 文件
+func (f Feed) RSS() (string, error) {
+	if err := f.ValidateRSS(); err != nil {
+		return "", err
+	}
+
+	channel := []any{
+		Element("title", nil, f.Title),
+		Element("link", nil, f.Link),
+		Element("description", nil, f.Description),
+	}
+	if f.Language != "" {
+		channel = append(channel, Element("language", nil, f.Language))
+	}
+	if updated := f.updated(); !updated.IsZero() {
+		channel = append(channel, Element("lastBuildDate", nil, updated.Format(time.RFC1123Z)))
+	}
+	if f.FeedURL != "" {
+		channel = append(channel, Element("atom:link", Props{"href": f.FeedURL, "rel": "self", "type": "application/rss+xml"}))
+	}
+
+	for _, it := range f.Items {
+		item := []any{}
+		if it.Title != "" {
+			item = append(item, Element("title", nil, it.Title))
+		}
+		if it.Link != "" {
+			item = append(item, Element("link", nil, it.Link))
+		}
+		if it.Summary != "" {
+			item = append(item, Element("description", nil, it.Summary))
+		}
+		if hasContent(it.Content) {
+			item = append(item, Element("content:encoded", nil, Render(it.Content)))
+		}
+		if it.Author != nil && it.Author.Email != "" {
+			item = append(item, Element("author", nil, rssAuthor(*it.Author)))
+		}
+		for _, c := range it.Categories {
+			item = append(item, Element("category", nil, c))
+		}
+		if id := it.atomID(); id != "" {
+			item = append(item, Element("guid", Props{"isPermaLink": fmt.Sprint(id == it.Link)}, id))
+		}
+		if !it.Published.IsZero() {
+			item = append(item, Element("pubDate", nil, it.Published.Format(time.RFC1123Z)))
+		}
+		channel = append(channel, Element("item", nil, item...))
+	}
+
+	rss := Element("rss", Props{
+		"version":       "2.0",
+		"xmlns:atom":    AtomNamespace,
+		"xmlns:content": contentNamespace,
+	}, Element("channel", nil, channel...))
+	return XMLDocument(rss), nil
+}
+
+// Atom 驗證並輸出 Atom 1.0 文件
+func (f Feed) Atom() (string, error) {
+	if err := f.ValidateAtom(); err != nil {
+		return "", err
+	}
+
+	children := []any{
+		Element("id", nil, f.atomID()),
+		Element("title", nil, f.Title),
+		Element("updated", nil, f.updated().Format(time.RFC3339)),
+	}
+	if f.Description != "" {
+		children = append(children, Element("subtitle", nil, f.Description))
+	}
+	if f.Link != "" {
+		children = append(children, Element("link", Props{"href": f.Link, "rel": "alternate"}))
+	}
+	if f.FeedURL != "" {
+		children = append(children, Element("link", Props{"href": f.FeedURL, "rel": "self"}))
+	}
+	if f.Author != nil {
+		children = append(children, atomAuthor(*f.Author))
+	}
+
+	for _, it := range f.Items {
+		entry := []any{
+			Element("id", nil, it.atomID()),
+			Element("title", nil, it.Title),
+			Element("updated", nil, it.updated().Format(time.RFC3339)),
+		}
+		if !it.Published.IsZero() {
+			entry = append(entry, Element("published", nil, it.Published.Format(time.RFC3339)))
+		}
+		if it.Link != "" {
+			entry = append(entry, Element("link", Props{"href": it.Link, "rel": "alternate"}))
+		}
+		if it.Author != nil {
+			entry = append(entry, atomAuthor(*it.Author))
+		}
+		for _, c := range it.Categories {
+			entry = append(entry, Element("category", Props{"term": c}))
+		}
+		if it.Summary != "" {
+			entry = append(entry, Element("summary", Props{"type": "text"}, it.Summary))
+		}
+		if hasContent(it.Content) {
+			entry = append(entry, Element("content", Props{"type": "html"}, Render(it.Content)))
+		}
+		children = append(children, Element("entry", nil, entry...))
+	}
+
+	return XMLDocument(Element("feed", Props{"xmlns": AtomNamespace}, children...)), nil
+}
+
+func (f Feed) atomID() string {
+	if f.ID != "" {
+		return f.ID
+	}
+	return f.Link
+}
+
+// updated 返回 feed 的更新時間；未設定時取所有項目中最新的時間
+func (f Feed) updated() time.Time {
+	if !f.Updated.IsZero() {
+		return f.Updated
+	}
+	var latest time.Time
+	for _, it := range f.Items {
+		if u := it.updated(); u.After(latest) {
+			latest = u
+		}
+	}
+	return latest
+}
+
+func (it Item) atomID() string {
+	if it.ID != "" {
+		return it.ID
+	}
+	return it.Link
+}
+
+func (it Item) updated() time.Time {
+	if !it.Updated.IsZero() {
+		return it.Updated
+	}
+	return it.Published
+}
+
+func atomAuthor(a Author) VNode {
+	children := []any{Element("name", nil, a.Name)}
+	if a.Email != "" {
+		children = append(children, Element("email", nil, a.Email))
+	}
+	if a.URI != "" {
+		children = append(children, Element("uri", nil, a.URI))
+	}
+	return Element("author", nil, children...)
+}
+
+// rssAuthor 依 RSS 2.0 慣例輸出 "email (name)"
+func rssAuthor(a Author) string {
+	if a.Name == "" {
+		return a.Email
+	}
+	return fmt.Sprintf("%s (%s)", a.Email, a.Name)
+}
+
+func hasContent(v VNode) bool {
+	return v.Tag != "" || v.Content != "" || len(v.Children) > 0
+}
+
+// isIRI 判斷是否為帶 scheme 的絕對 IRI（例如 https://… 或 urn:uuid:…）
+func isIRI(raw string) bool {
+	u, err := url.Parse(raw)
+	return err == nil && u.Scheme != ""
+}
@@ -0,0 +1,227 @@
+package feed
+
+import (
+	"encoding/xml"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/TimLai666/go-vdom/dom"
+)
+
+var testTime = time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+
+func TestSitemapXML(t *testing.T) {
+	sm := SitemapFromPaths("https://example.com/", []string{"/", "about", "/search?q=a&b"})
+	sm.URLs[0].LastMod = testTime
+	sm.URLs[0].ChangeFreq = Daily
+	sm.URLs[0].Priority = 1
+
+	out, err := sm.XML()
+	if err != nil {
+		t.Fatalf("XML() error: %v", err)
+	}
+	if !strings.HasPrefix(out, `<?xml version="1.0" encoding="UTF-8"?>`) {
+		t.Errorf("missing XML declaration: %s", out)
+	}
+
+	var parsed struct {
+		XMLName xml.Name `xml:"http://www.sitemaps.org/schemas/sitemap/0.9 urlset"`
+		URLs    []struct {
+			Loc        string `xml:"loc"`
+			LastMod    string `xml:"lastmod"`
+			ChangeFreq string `xml:"changefreq"`
+			Priority   string `xml:"priority"`
+		} `xml:"url"`
+	}
+	if err := xml.Unmarshal([]byte(out), &parsed); err != nil {
+		t.Fatalf("output is not well-formed: %v\n%s", err, out)
+	}
+	if len(parsed.URLs) != 3 {
+		t.Fatalf("got %d urls, want 3", len(parsed.URLs))
+	}
+	first := parsed.URLs[0]
+	if first.Loc != "https://example.com/" || first.LastMod != "2025-05-01T12:00:00Z" || first.ChangeFreq != "daily" || first.Priority != "1.0" {
+		t.Errorf("unexpected first url: %+v", first)
+	}
+	if parsed.URLs[1].Loc != "https://example.com/about" {
+		t.Errorf("relative path not joined: %q", parsed.URLs[1].Loc)
+	}
+	if parsed.URLs[2].Loc != "https://example.com/search?q=a&b" {
+		t.Errorf("loc did not round-trip through escaping: %q", parsed.URLs[2].Loc)
+	}
+	if parsed.URLs[1].LastMod != "" || parsed.URLs[1].Priority != "" {
+		t.Errorf("optional fields should be omitted: %+v", parsed.URLs[1])
+	}
+}
+
+func TestSitemapValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		url  URL
+		want string
+	}{
+		{"missing loc", URL{}, "loc is required"},
+		{"relative loc", URL{Loc: "/about"}, "absolute http(s) URL"},
+		{"bad changefreq", URL{Loc: "https://a.com/", ChangeFreq: "sometimes"}, "invalid changefreq"},
+		{"priority too high", URL{Loc: "https://a.com/", Priority: 1.5}, "priority"},
+		{"priority negative", URL{Loc: "https://a.com/", Priority: -0.1}, "priority"},
+		{"priority NaN", URL{Loc: "https://a.com/", Priority: math.NaN()}, "priority"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Sitemap{URLs: []URL{tt.url}}.XML()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want containing %q", err, tt.want)
+			}
+		})
+	}
+
+	if err := (Sitemap{URLs: []URL{{Loc: "https://a.com/"}}}).Validate(); err != nil {
+		t.Errorf("valid sitemap reported error: %v", err)
+	}
+}
+
+func TestSitemapPriorityPrecision(t *testing.T) {
+	for p, want := range map[float64]string{0.75: "0.75", 0.5: "0.5", 1: "1.0", 0.125: "0.125"} {
+		out, err := Sitemap{URLs: []URL{{Loc: "https://a.com/", Priority: p}}}.XML()
+		if err != nil {
+			t.Fatalf("XML() error: %v", err)
+		}
+		if !strings.Contains(out, "<priority>"+want+"</priority>") {
+			t.Errorf("priority %v: want <priority>%s</priority> in %s", p, want, out)
+		}
+	}
+}
+
+func testFeed() Feed {
+	return Feed{
+		Title:       "Go VDOM 部落格",
+		Link:        "https://example.com/",
+		FeedURL:     "https://example.com/feed.xml",
+		Description: "最新文章",
+		Author:      &Author{Name: "Tim", Email: "tim@example.com"},
+		Items: []Item{
+			{
+				Title:     "Hello <World>",
+				Link:      "https://example.com/posts/1",
+				Summary:   "第一篇",
+				Content:   Div(nil, P(nil, "Hi & bye"), Script(nil, "</script>")),
+				Published: testTime,
+			},
+			{
+				ID:        "urn:uuid:60a76c80-d399-11d9-b93C-0003939e0af6",
+				Title:     "Second",
+				Content:   P(nil, "second"),
+				Published: testTime.Add(time.Hour),
+			},
+		},
+	}
+}
+
+func TestAtomRequiredFields(t *testing.T) {
+	out, err := testFeed().Atom()
+	if err != nil {
+		t.Fatalf("Atom() error: %v", err)
+	}
+
+	var parsed struct {
+		XMLName xml.Name `xml:"http://www.w3.org/2005/Atom feed"`
+		ID      string   `xml:"id"`
+		Title   string   `xml:"title"`
+		Updated string   `xml:"updated"`
+		Author  struct {
+			Name string `xml:"name"`
+		} `xml:"author"`
+		Entries []struct {
+			ID      string `xml:"id"`
+			Title   string `xml:"title"`
+			Updated string `xml:"updated"`
+			Content struct {
+				Type string `xml:"type,attr"`
+				Body string `xml:",chardata"`
+			} `xml:"content"`
+		} `xml:"entry"`
+	}
+	if err := xml.Unmarshal([]byte(out), &parsed); err != nil {
+		t.Fatalf("output is not well-formed: %v\n%s", err, out)
+	}
+
+	if parsed.ID != "https://example.com/" || parsed.Title == "" || parsed.Author.Name != "Tim" {
+		t.Errorf("missing feed-level fields: %+v", parsed)
+	}
+	if parsed.Updated != "2025-05-01T13:00:00Z" {
+		t.Errorf("feed updated = %q, want latest entry time", parsed.Updated)
+	}
+	if len(parsed.Entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(parsed.Entries))
+	}
+	first := parsed.Entries[0]
+	if first.ID != "https://example.com/posts/1" || first.Title != "Hello <World>" || first.Updated != "2025-05-01T12:00:00Z" {
+		t.Errorf("unexpected first entry: %+v", first)
+	}
+	if first.Content.Type != "html" || !strings.Contains(first.Content.Body, "<p>Hi & bye</p>") {
+		t.Errorf("content should carry rendered HTML, got %+v", first.Content)
+	}
+}
+
+func TestAtomValidate(t *testing.T) {
+	f := testFeed()
+	f.Author = nil
+	f.Items[1].ID = "not an iri"
+	f.Items[1].Title = ""
+
+	err := f.ValidateAtom()
+	if err == nil {
+		t.Fatal("expected validation error")
+	}
+	for _, want := range []string{"entry[0]: author is required", "entry[1]: id", "entry[1]: title is required"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q should mention %q", err, want)
+		}
+	}
+
+	if _, err := (Feed{}).Atom(); err == nil || !strings.Contains(err.Error(), "feed id is required") {
+		t.Errorf("empty feed error = %v", err)
+	}
+}
+
+func TestRSS(t *testing.T) {
+	out, err := testFeed().RSS()
+	if err != nil {
+		t.Fatalf("RSS() error: %v", err)
+	}
+
+	var parsed struct {
+		Version string `xml:"version,attr"`
+		Channel struct {
+			Title       string   `xml:"title"`
+			Links       []string `xml:"link"` // 包含 <link> 與 <atom:link>
+			Description string   `xml:"description"`
+			Items       []struct {
+				Title   string `xml:"title"`
+				GUID    string `xml:"guid"`
+				PubDate string `xml:"pubDate"`
+				Content string `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+			} `xml:"item"`
+		} `xml:"channel"`
+	}
+	if err := xml.Unmarshal([]byte(out), &parsed); err != nil {
+		t.Fatalf("output is not well-formed: %v\n%s", err, out)
+	}
+	if parsed.Version != "2.0" || parsed.Channel.Title == "" || len(parsed.Channel.Links) == 0 || parsed.Channel.Links[0] != "https://example.com/" || parsed.Channel.Description == "" {
+		t.Errorf("missing channel fields: %+v", parsed)
+	}
+	item := parsed.Channel.Items[0]
+	if item.PubDate != "Thu, 01 May 2025 12:00:00 +0000" || item.GUID != "https://example.com/posts/1" {
+		t.Errorf("unexpected item: %+v", item)
+	}
+	if !strings.Contains(item.Content, "<p>Hi & bye</p>") {
+		t.Errorf("content:encoded = %q", item.Content)
+	}
+
+	if err := (Feed{Title: "x"}).ValidateRSS(); err == nil || !strings.Contains(err.Error(), "channel link") {
+		t.Errorf("ValidateRSS error = %v", err)
+	}
+}
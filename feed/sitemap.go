@@ -0,0 +1,140 @@
+// sitemap.go
+package feed
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	. "github.com/TimLai666/go-vdom/dom"
+)
+
+// SitemapNamespace 是 sitemaps.org 協議的 XML 命名空間
+const SitemapNamespace = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+// MaxSitemapURLs 是單一 sitemap 允許的最大 URL 數量
+const MaxSitemapURLs = 50000
+
+// ChangeFreq 表示頁面預期的更新頻率
+type ChangeFreq string
+
+const (
+	Always  ChangeFreq = "always"
+	Hourly  ChangeFreq = "hourly"
+	Daily   ChangeFreq = "daily"
+	Weekly  ChangeFreq = "weekly"
+	Monthly ChangeFreq = "monthly"
+	Yearly  ChangeFreq = "yearly"
+	Never   ChangeFreq = "never"
+)
+
+// URL 是 sitemap 中的一個 <url> 項目
+type URL struct {
+	Loc        string     // 必填，頁面的絕對網址
+	LastMod    time.Time  // 最後修改時間，零值表示不輸出
+	ChangeFreq ChangeFreq // 更新頻率，空字串表示不輸出
+	Priority   float64    // 優先度 0.0 ~ 1.0，0 表示不輸出（使用預設 0.5）
+}
+
+// Sitemap 是一個 sitemap.xml 文件
+type Sitemap struct {
+	URLs []URL
+}
+
+// SitemapFromPaths 以站點根網址與路由路徑建立 Sitemap
+// 用法：SitemapFromPaths("https://example.com", []string{"/", "/about"})
+func SitemapFromPaths(baseURL string, paths []string) Sitemap {
+	base := strings.TrimSuffix(baseURL, "/")
+	sm := Sitemap{URLs: make([]URL, 0, len(paths))}
+	for _, p := range paths {
+		if !strings.HasPrefix(p, "/") {
+			p = "/" + p
+		}
+		sm.URLs = append(sm.URLs, URL{Loc: base + p})
+	}
+	return sm
+}
+
+// Validate 檢查 sitemap 是否符合 sitemaps.org 協議的必要欄位
+func (s Sitemap) Validate() error {
+	var errs []error
+	if len(s.URLs) > MaxSitemapURLs {
+		errs = append(errs, fmt.Errorf("sitemap: %d urls exceeds limit of %d", len(s.URLs), MaxSitemapURLs))
+	}
+	for i, u := range s.URLs {
+		if err := u.validate(); err != nil {
+			errs = append(errs, fmt.Errorf("sitemap: url[%d]: %w", i, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (u URL) validate() error {
+	if u.Loc == "" {
+		return errors.New("loc is required")
+	}
+	if len(u.Loc) >= 2048 {
+		return fmt.Errorf("loc %q must be shorter than 2048 characters", u.Loc)
+	}
+	if !isAbsoluteHTTP(u.Loc) {
+		return fmt.Errorf("loc %q must be an absolute http(s) URL", u.Loc)
+	}
+	switch u.ChangeFreq {
+	case "", Always, Hourly, Daily, Weekly, Monthly, Yearly, Never:
+	default:
+		return fmt.Errorf("invalid changefreq %q", u.ChangeFreq)
+	}
+	if math.IsNaN(u.Priority) || u.Priority < 0 || u.Priority > 1 {
+		return fmt.Errorf("priority %v must be between 0.0 and 1.0", u.Priority)
+	}
+	return nil
+}
+
+// VNode 返回 sitemap 的 <urlset> 節點
+func (s Sitemap) VNode() VNode {
+	urls := make([]VNode, 0, len(s.URLs))
+	for _, u := range s.URLs {
+		children := []any{Element("loc", nil, u.Loc)}
+		if !u.LastMod.IsZero() {
+			children = append(children, Element("lastmod", nil, u.LastMod.Format(time.RFC3339)))
+		}
+		if u.ChangeFreq != "" {
+			children = append(children, Element("changefreq", nil, string(u.ChangeFreq)))
+		}
+		if u.Priority > 0 {
+			children = append(children, Element("priority", nil, formatPriority(u.Priority)))
+		}
+		urls = append(urls, Element("url", nil, children...))
+	}
+	return Element("urlset", Props{"xmlns": SitemapNamespace}, urls)
+}
+
+// XML 驗證並輸出完整的 sitemap.xml 內容
+func (s Sitemap) XML() (string, error) {
+	if err := s.Validate(); err != nil {
+		return "", err
+	}
+	return XMLDocument(s.VNode()), nil
+}
+
+// formatPriority 以最短的精確表示輸出優先度（0.75 不會被四捨五入為 0.8），整數保留一位小數
+func formatPriority(p float64) string {
+	s := strconv.FormatFloat(p, 'f', -1, 64)
+	if !strings.Contains(s, ".") {
+		s += ".0"
+	}
+	return s
+}
+
+// isAbsoluteHTTP 判斷是否為帶主機名的 http/https 網址
+func isAbsoluteHTTP(raw string) bool {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return false
+	}
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
@@ -0,0 +1,116 @@
+package devserver
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TimLai666/go-vdom/dom"
+	"github.com/TimLai666/go-vdom/runtime"
+)
+
+func TestWatcherDetectsGoChanges(t *testing.T) {
+	dir := t.TempDir()
+	mainGo := filepath.Join(dir, "main.go")
+	if err := os.WriteFile(mainGo, []byte("package main"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, ".git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	w, err := NewWatcher(dir, time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changed, _ := w.Changed(); len(changed) != 0 {
+		t.Fatalf("no changes expected, got %v", changed)
+	}
+
+	// 非 .go 檔案與隱藏目錄中的檔案不應觸發重建
+	_ = os.WriteFile(filepath.Join(dir, "README.md"), []byte("x"), 0o644)
+	_ = os.WriteFile(filepath.Join(dir, ".git", "hook.go"), []byte("x"), 0o644)
+	if changed, _ := w.Changed(); len(changed) != 0 {
+		t.Fatalf("ignored files reported as changed: %v", changed)
+	}
+
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(mainGo, later, later); err != nil {
+		t.Fatal(err)
+	}
+	changed, err := w.Changed()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changed) != 1 || changed[0] != mainGo {
+		t.Fatalf("changed = %v, want [%s]", changed, mainGo)
+	}
+
+	if err := os.Remove(mainGo); err != nil {
+		t.Fatal(err)
+	}
+	if changed, _ := w.Changed(); len(changed) != 1 {
+		t.Fatalf("deleted file should be reported, got %v", changed)
+	}
+}
+
+func TestReloaderBroadcasts(t *testing.T) {
+	rl := NewReloader()
+	srv := httptest.NewServer(rl)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	if resp.Header.Get("Access-Control-Allow-Origin") != "*" {
+		t.Error("reload endpoint should allow cross-origin EventSource")
+	}
+
+	reader := bufio.NewReader(resp.Body)
+	if line, _ := reader.ReadString('\n'); !strings.HasPrefix(line, ": connected") {
+		t.Fatalf("first line = %q", line)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for rl.Clients() == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	rl.Reload()
+
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("stream ended before reload event: %v", err)
+		}
+		if strings.TrimSpace(line) == "event: reload" {
+			break
+		}
+	}
+}
+
+func TestDocumentInjectsReloadScriptInDevMode(t *testing.T) {
+	t.Setenv(runtime.DevReloadEnv, "")
+	if html := dom.Render(dom.Document("t", nil, nil, nil)); strings.Contains(html, "__gvdDevReload") {
+		t.Error("reload script should not be injected outside dev mode")
+	}
+
+	t.Setenv(runtime.DevReloadEnv, "http://127.0.0.1:35729"+ReloadPath)
+	html := dom.Render(dom.Document("t", nil, nil, nil))
+	if !strings.Contains(html, "new EventSource('http://127.0.0.1:35729/__gvd/reload')") {
+		t.Errorf("reload script missing from dev-mode document: %s", html)
+	}
+	if !strings.Contains(html, "reloadWhenReady(100)") {
+		t.Errorf("reload should wait for the restarted server to respond: %s", html)
+	}
+}
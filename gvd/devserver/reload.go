@@ -0,0 +1,79 @@
+// reload.go
+package devserver
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// Reloader 是一個 Server-Sent Events 端點，向所有開啟中的瀏覽器廣播 reload 事件
+type Reloader struct {
+	mu      sync.Mutex
+	clients map[chan struct{}]struct{}
+}
+
+// NewReloader 創建一個 Reloader
+func NewReloader() *Reloader {
+	return &Reloader{clients: make(map[chan struct{}]struct{})}
+}
+
+// Reload 通知所有已連線的瀏覽器重新載入頁面
+func (rl *Reloader) Reload() {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	for ch := range rl.clients {
+		select {
+		case ch <- struct{}{}:
+		default:
+			// 已有待送出的 reload，無需重複
+		}
+	}
+}
+
+// Clients 返回目前連線中的瀏覽器數量
+func (rl *Reloader) Clients() int {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	return len(rl.clients)
+}
+
+// ServeHTTP 保持 SSE 連線直到客戶端離開
+// 開發伺服器與使用者的伺服器埠號不同，因此允許跨來源存取
+func (rl *Reloader) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	h := w.Header()
+	h.Set("Content-Type", "text/event-stream")
+	h.Set("Cache-Control", "no-cache")
+	h.Set("Connection", "keep-alive")
+	h.Set("Access-Control-Allow-Origin", "*")
+
+	ch := make(chan struct{}, 1)
+	rl.mu.Lock()
+	rl.clients[ch] = struct{}{}
+	rl.mu.Unlock()
+	defer func() {
+		rl.mu.Lock()
+		delete(rl.clients, ch)
+		rl.mu.Unlock()
+	}()
+
+	// 初始註解讓瀏覽器立即完成連線
+	fmt.Fprint(w, ": connected\n\n")
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ch:
+			fmt.Fprint(w, "event: reload\ndata: {}\n\n")
+			flusher.Flush()
+		}
+	}
+}
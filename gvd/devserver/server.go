@@ -0,0 +1,187 @@
+// server.go
+package devserver
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	goruntime "runtime"
+	"time"
+
+	"github.com/TimLai666/go-vdom/runtime"
+)
+
+// ReloadPath 是 reload SSE 端點的路徑
+const ReloadPath = "/__gvd/reload"
+
+// Config 是開發伺服器的設定
+type Config struct {
+	Dir      string        // 監看 .go 檔案的專案根目錄，預設 "."
+	Pkg      string        // 要建置並執行的 main 套件，預設 "."
+	Args     []string      // 傳給使用者程式的參數
+	Addr     string        // reload 端點的監聽位址，預設 "127.0.0.1:35729"
+	AppAddr  string        // 使用者伺服器的位址（如 "localhost:8080"）；設定後會等到可連線才通知瀏覽器，未設定時由瀏覽器輪詢頁面直到伺服器回應
+	Interval time.Duration // 檔案輪詢間隔，預設 500ms
+	Stdout   io.Writer     // 使用者程式與建置訊息的輸出，預設 os.Stdout
+	Stderr   io.Writer     // 預設 os.Stderr
+}
+
+// Run 建置並啟動使用者的伺服器，監看 .go 檔案的變更，
+// 每次變更後重新建置、重啟，並透過 SSE 通知瀏覽器重新載入
+// 使用者的程式會收到 runtime.DevReloadEnv 環境變數，使 Document 注入重新整理腳本
+// ctx 取消時停止伺服器並返回
+func Run(ctx context.Context, cfg Config) error {
+	if cfg.Dir == "" {
+		cfg.Dir = "."
+	}
+	if cfg.Pkg == "" {
+		cfg.Pkg = "."
+	}
+	if cfg.Addr == "" {
+		cfg.Addr = "127.0.0.1:35729"
+	}
+	if cfg.Stdout == nil {
+		cfg.Stdout = os.Stdout
+	}
+	if cfg.Stderr == nil {
+		cfg.Stderr = os.Stderr
+	}
+
+	ln, err := net.Listen("tcp", cfg.Addr)
+	if err != nil {
+		return fmt.Errorf("devserver: listen: %w", err)
+	}
+	reloader := NewReloader()
+	mux := http.NewServeMux()
+	mux.Handle(ReloadPath, reloader)
+	srv := &http.Server{Handler: mux}
+	go srv.Serve(ln)
+	defer srv.Close()
+
+	tmp, err := os.MkdirTemp("", "gvd-dev-")
+	if err != nil {
+		return fmt.Errorf("devserver: temp dir: %w", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	app := &process{
+		cfg:      cfg,
+		binary:   filepath.Join(tmp, binaryName()),
+		endpoint: "http://" + ln.Addr().String() + ReloadPath,
+	}
+	if err := app.build(); err != nil {
+		fmt.Fprintf(cfg.Stderr, "gvd dev: build failed:\n%v\n", err)
+	} else if err := app.start(); err != nil {
+		return err
+	}
+	defer app.stop()
+
+	watcher, err := NewWatcher(cfg.Dir, cfg.Interval)
+	if err != nil {
+		return fmt.Errorf("devserver: watch: %w", err)
+	}
+
+	fmt.Fprintf(cfg.Stdout, "gvd dev: watching %s, reload endpoint %s\n", cfg.Dir, app.endpoint)
+	watcher.Watch(ctx.Done(), func(changed []string) {
+		fmt.Fprintf(cfg.Stdout, "gvd dev: %d file(s) changed, rebuilding\n", len(changed))
+		if err := app.build(); err != nil {
+			// 建置失敗時保留目前執行中的版本
+			fmt.Fprintf(cfg.Stderr, "gvd dev: build failed:\n%v\n", err)
+			return
+		}
+		app.stop()
+		if err := app.start(); err != nil {
+			fmt.Fprintf(cfg.Stderr, "gvd dev: %v\n", err)
+			return
+		}
+		if cfg.AppAddr != "" {
+			waitForAddr(ctx, cfg.AppAddr, 10*time.Second)
+		}
+		reloader.Reload()
+	}, func(err error) {
+		fmt.Fprintf(cfg.Stderr, "gvd dev: watch: %v\n", err)
+	})
+	return nil
+}
+
+// process 管理使用者伺服器的建置與執行
+type process struct {
+	cfg      Config
+	binary   string
+	endpoint string
+	cmd      *exec.Cmd
+	done     chan struct{}
+}
+
+func (p *process) build() error {
+	cmd := exec.Command("go", "build", "-o", p.binary, p.cfg.Pkg)
+	cmd.Dir = p.cfg.Dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%w\n%s", err, out)
+	}
+	return nil
+}
+
+func (p *process) start() error {
+	cmd := exec.Command(p.binary, p.cfg.Args...)
+	cmd.Dir = p.cfg.Dir
+	cmd.Stdout = p.cfg.Stdout
+	cmd.Stderr = p.cfg.Stderr
+	cmd.Env = append(os.Environ(), runtime.DevReloadEnv+"="+p.endpoint)
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("devserver: start: %w", err)
+	}
+	done := make(chan struct{})
+	go func() {
+		_ = cmd.Wait()
+		close(done)
+	}()
+	p.cmd, p.done = cmd, done
+	return nil
+}
+
+// stop 先嘗試以中斷訊號讓程式正常結束，逾時後強制終止
+func (p *process) stop() {
+	if p.cmd == nil {
+		return
+	}
+	if err := p.cmd.Process.Signal(os.Interrupt); err != nil {
+		_ = p.cmd.Process.Kill()
+	}
+	select {
+	case <-p.done:
+	case <-time.After(3 * time.Second):
+		_ = p.cmd.Process.Kill()
+		<-p.done
+	}
+	p.cmd, p.done = nil, nil
+}
+
+// waitForAddr 等待位址可以建立 TCP 連線，或直到逾時
+func waitForAddr(ctx context.Context, addr string, timeout time.Duration) {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		conn, err := net.DialTimeout("tcp", addr, 200*time.Millisecond)
+		if err == nil {
+			conn.Close()
+			return
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+func binaryName() string {
+	if goruntime.GOOS == "windows" {
+		return "app.exe"
+	}
+	return "app"
+}
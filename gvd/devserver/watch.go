@@ -0,0 +1,109 @@
+// watch.go
+package devserver
+
+import (
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// fileState 記錄檔案的修改時間與大小
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// Watcher 以輪詢方式監看目錄下的 .go 檔案
+// 只使用標準庫，因此在各平台上行為一致；隱藏目錄、vendor 與 node_modules 會被略過
+type Watcher struct {
+	Root     string
+	Interval time.Duration // 輪詢間隔，預設 500ms
+
+	snapshot map[string]fileState
+}
+
+// NewWatcher 創建一個 Watcher 並記錄目前的檔案狀態
+func NewWatcher(root string, interval time.Duration) (*Watcher, error) {
+	if interval <= 0 {
+		interval = 500 * time.Millisecond
+	}
+	w := &Watcher{Root: root, Interval: interval}
+	snap, err := w.scan()
+	if err != nil {
+		return nil, err
+	}
+	w.snapshot = snap
+	return w, nil
+}
+
+// Changed 重新掃描檔案，返回自上次掃描以來新增、修改或刪除的檔案
+func (w *Watcher) Changed() ([]string, error) {
+	snap, err := w.scan()
+	if err != nil {
+		return nil, err
+	}
+
+	var changed []string
+	for p, st := range snap {
+		if old, ok := w.snapshot[p]; !ok || !old.modTime.Equal(st.modTime) || old.size != st.size {
+			changed = append(changed, p)
+		}
+	}
+	for p := range w.snapshot {
+		if _, ok := snap[p]; !ok {
+			changed = append(changed, p)
+		}
+	}
+	w.snapshot = snap
+	return changed, nil
+}
+
+// Watch 每隔 Interval 檢查一次，有變更時呼叫 onChange；stop 關閉時返回
+func (w *Watcher) Watch(stop <-chan struct{}, onChange func(changed []string), onError func(error)) {
+	ticker := time.NewTicker(w.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			changed, err := w.Changed()
+			if err != nil {
+				if onError != nil {
+					onError(err)
+				}
+				continue
+			}
+			if len(changed) > 0 {
+				onChange(changed)
+			}
+		}
+	}
+}
+
+func (w *Watcher) scan() (map[string]fileState, error) {
+	snap := make(map[string]fileState)
+	err := filepath.WalkDir(w.Root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			name := d.Name()
+			if p != w.Root && (strings.HasPrefix(name, ".") || name == "vendor" || name == "node_modules") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(p, ".go") {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		snap[p] = fileState{modTime: info.ModTime(), size: info.Size()}
+		return nil
+	})
+	return snap, err
+}
@@ -0,0 +1,43 @@
+// fragment.go
+package gvd
+
+import (
+	"strings"
+
+	"github.com/TimLai666/go-vdom/dom"
+)
+
+// TargetHeader 是片段請求的標頭，值為目標元素的 id 選擇器，例如 "#content"
+// runtime 處理 data-gvd-get / data-gvd-post 時會自動帶上此標頭
+const TargetHeader = "GVD-Target"
+
+// targetID 從 "#content" 形式的標頭值取出 id；不是 id 選擇器時返回空字串
+func targetID(header string) string {
+	id, ok := strings.CutPrefix(strings.TrimSpace(header), "#")
+	if !ok {
+		return ""
+	}
+	return id
+}
+
+// Fragment 返回 header 指定的子樹；header 為空時返回整個節點
+// header 不是 id 選擇器或樹中沒有該 id 時第二個返回值為 false
+func Fragment(node dom.VNode, header string) (dom.VNode, bool) {
+	if strings.TrimSpace(header) == "" {
+		return node, true
+	}
+	id := targetID(header)
+	if id == "" {
+		return dom.VNode{}, false
+	}
+	return dom.FindByID(node, id)
+}
+
+// 片段替換模式，對應 runtime 的 data-gvd-swap 屬性值
+const (
+	SwapInnerHTML  = "innerHTML"  // 以片段取代目標的子節點（預設）
+	SwapOuterHTML  = "outerHTML"  // 以片段取代目標元素本身
+	SwapBeforeEnd  = "beforeend"  // 將片段附加到目標的子節點末端
+	SwapAfterBegin = "afterbegin" // 將片段插入到目標的子節點開頭
+	SwapMorph      = "morph"      // 就地比對更新目標，保留焦點與輸入狀態
+)
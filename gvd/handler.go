@@ -0,0 +1,156 @@
+// handler.go
+package gvd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/TimLai666/go-vdom/dom"
+)
+
+// Handler 將返回 VNode 的函數轉為 http.Handler
+//   - 設定 Content-Type、Content-Length 與由渲染結果計算的強 ETag
+//   - If-None-Match 符合時回應 304 Not Modified
+//   - HEAD 請求只回應標頭
+//   - Accept 明確偏好 application/json 時，回應 ToCompactJSON 的結果，方便除錯
+//   - 帶有 GVD-Target: #id 標頭時只回應該 id 的子樹（HTML 片段），供 runtime 局部替換
+//   - 函數返回錯誤時回應錯誤狀態碼；錯誤實作 StatusCode() int 時使用該狀態碼，否則為 500
+//
+// 注意：未指定 id 的組件每次渲染都會得到新的自動 id，使 ETag 在每次請求都不同；
+// 需要快取的頁面應為組件提供固定的 id。
+//
+// 用法：
+//
+//	http.Handle("/", gvd.Handler(func(r *http.Request) (VNode, error) {
+//	    return Document("首頁", nil, nil, nil, H1(nil, "Hello")), nil
+//	}))
+func Handler(fn func(r *http.Request) (dom.VNode, error)) http.Handler {
+	return handlerFunc(fn)
+}
+
+type handlerFunc func(r *http.Request) (dom.VNode, error)
+
+func (h handlerFunc) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	node, err := h(r)
+	if err != nil {
+		code := http.StatusInternalServerError
+		var sc interface{ StatusCode() int }
+		if errors.As(err, &sc) {
+			code = sc.StatusCode()
+		}
+		http.Error(w, http.StatusText(code), code)
+		return
+	}
+	Write(w, r, http.StatusOK, node)
+}
+
+// Write 將節點依請求協商的格式寫入回應，套用與 Handler 相同的標頭、ETag 與 HEAD 處理
+// 只有 200 回應會以 If-None-Match 判斷 304；片段請求的目標不存在時回應 404
+func Write(w http.ResponseWriter, r *http.Request, status int, node dom.VNode) {
+	node, ok := Fragment(node, r.Header.Get(TargetHeader))
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		return
+	}
+
+	var body, contentType string
+	if prefersJSON(r.Header.Get("Accept")) {
+		data, err := dom.ToCompactJSON(node)
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		body, contentType = data, "application/json; charset=utf-8"
+	} else {
+		body, contentType = RenderPage(node), "text/html; charset=utf-8"
+	}
+
+	etag := ETag(body)
+	h := w.Header()
+	h.Add("Vary", "Accept")
+	h.Add("Vary", TargetHeader)
+	h.Set("ETag", etag)
+
+	if status == http.StatusOK && etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	h.Set("Content-Type", contentType)
+	h.Set("Content-Length", strconv.Itoa(len(body)))
+	w.WriteHeader(status)
+	if r.Method != http.MethodHead {
+		_, _ = w.Write([]byte(body))
+	}
+}
+
+// RenderPage 渲染完整頁面；根節點為 <html> 時補上 doctype
+func RenderPage(node dom.VNode) string {
+	if node.Tag == "html" {
+		return "<!DOCTYPE html>\n" + dom.Render(node)
+	}
+	return dom.Render(node)
+}
+
+// ETag 返回內容的強 ETag（帶引號的 SHA-256 前 32 個十六進位字元）
+func ETag(body string) string {
+	sum := sha256.Sum256([]byte(body))
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// etagMatches 依 RFC 9110 以弱比較判斷 If-None-Match 是否符合
+func etagMatches(header, etag string) bool {
+	if header == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// prefersJSON 判斷 Accept 標頭是否明確要求 application/json 且其權重高於 HTML
+// 瀏覽器預設的 Accept（text/html,...,*/*;q=0.8）不會被視為要求 JSON
+func prefersJSON(accept string) bool {
+	if accept == "" {
+		return false
+	}
+	jsonQ, htmlQ := -1.0, -1.0
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, q := parseMediaRange(part)
+		switch mediaType {
+		case "application/json":
+			jsonQ = q
+		case "text/html":
+			htmlQ = q
+		case "text/*", "*/*":
+			if htmlQ < 0 {
+				htmlQ = 0 // 萬用字元只在沒有明確 text/html 時作為最低偏好
+			}
+		}
+	}
+	return jsonQ > 0 && jsonQ > htmlQ
+}
+
+// parseMediaRange 解析 "type/subtype;q=0.5" 形式的媒體範圍
+func parseMediaRange(part string) (string, float64) {
+	fields := strings.Split(part, ";")
+	mediaType := strings.ToLower(strings.TrimSpace(fields[0]))
+	q := 1.0
+	for _, param := range fields[1:] {
+		key, val, ok := strings.Cut(strings.TrimSpace(param), "=")
+		if ok && strings.EqualFold(key, "q") {
+			if parsed, err := strconv.ParseFloat(val, 64); err == nil {
+				q = parsed
+			}
+		}
+	}
+	return mediaType, q
+}
@@ -0,0 +1,169 @@
+package gvd
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/TimLai666/go-vdom/dom"
+)
+
+type statusErr int
+
+func (e statusErr) Error() string   { return fmt.Sprintf("status %d", int(e)) }
+func (e statusErr) StatusCode() int { return int(e) }
+
+func testHandler() http.Handler {
+	return Handler(func(r *http.Request) (dom.VNode, error) {
+		switch r.URL.Path {
+		case "/missing":
+			return dom.VNode{}, fmt.Errorf("lookup: %w", statusErr(http.StatusNotFound))
+		case "/broken":
+			return dom.VNode{}, errors.New("boom")
+		}
+		return dom.Html(nil, dom.Body(nil, dom.H1(dom.Props{"id": "title", "class": "big"}, "Hello"))), nil
+	})
+}
+
+func serve(h http.Handler, req *http.Request) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestHandlerHTML(t *testing.T) {
+	rec := serve(testHandler(), httptest.NewRequest("GET", "/", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d", rec.Code)
+	}
+	body := rec.Body.String()
+	if body != "<!DOCTYPE html>\n"+`<html><body><h1 class="big" id="title">Hello</h1></body></html>` {
+		t.Errorf("unexpected body: %s", body)
+	}
+	if got := rec.Header().Get("Content-Type"); got != "text/html; charset=utf-8" {
+		t.Errorf("Content-Type = %q", got)
+	}
+	if got := rec.Header().Get("Content-Length"); got != fmt.Sprint(len(body)) {
+		t.Errorf("Content-Length = %q, body is %d bytes", got, len(body))
+	}
+	if etag := rec.Header().Get("ETag"); etag != ETag(body) || strings.HasPrefix(etag, "W/") {
+		t.Errorf("ETag = %q, want strong %q", etag, ETag(body))
+	}
+	if rec.Header().Get("Vary") != "Accept" {
+		t.Errorf("Vary = %q", rec.Header().Get("Vary"))
+	}
+}
+
+func TestHandlerConditionalGet(t *testing.T) {
+	h := testHandler()
+	etag := serve(h, httptest.NewRequest("GET", "/", nil)).Header().Get("ETag")
+
+	for _, inm := range []string{etag, `"other", ` + etag, "W/" + etag, "*"} {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("If-None-Match", inm)
+		rec := serve(h, req)
+		if rec.Code != http.StatusNotModified || rec.Body.Len() != 0 {
+			t.Errorf("If-None-Match %s: status = %d, body = %q", inm, rec.Code, rec.Body.String())
+		}
+		if rec.Header().Get("ETag") != etag {
+			t.Errorf("304 should repeat the ETag")
+		}
+	}
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("If-None-Match", `"stale"`)
+	if rec := serve(h, req); rec.Code != http.StatusOK {
+		t.Errorf("stale ETag: status = %d, want 200", rec.Code)
+	}
+}
+
+func TestHandlerHead(t *testing.T) {
+	get := serve(testHandler(), httptest.NewRequest("GET", "/", nil))
+	head := serve(testHandler(), httptest.NewRequest("HEAD", "/", nil))
+
+	if head.Code != http.StatusOK || head.Body.Len() != 0 {
+		t.Errorf("HEAD: status = %d, body = %q", head.Code, head.Body.String())
+	}
+	for _, k := range []string{"ETag", "Content-Length", "Content-Type"} {
+		if head.Header().Get(k) != get.Header().Get(k) {
+			t.Errorf("HEAD %s = %q, GET has %q", k, head.Header().Get(k), get.Header().Get(k))
+		}
+	}
+}
+
+func TestHandlerJSON(t *testing.T) {
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Accept", "application/json")
+	rec := serve(testHandler(), req)
+
+	if got := rec.Header().Get("Content-Type"); got != "application/json; charset=utf-8" {
+		t.Errorf("Content-Type = %q", got)
+	}
+	node, err := dom.FromJSON(rec.Body.String())
+	if err != nil {
+		t.Fatalf("body is not a VNode: %v", err)
+	}
+	if node.Tag != "html" || node.Children[0].Children[0].Props["id"] != "title" {
+		t.Errorf("unexpected JSON tree: %+v", node)
+	}
+
+	html := serve(testHandler(), httptest.NewRequest("GET", "/", nil))
+	if rec.Header().Get("ETag") == html.Header().Get("ETag") {
+		t.Error("JSON and HTML representations must have different ETags")
+	}
+}
+
+func TestPrefersJSON(t *testing.T) {
+	tests := map[string]bool{
+		"":                      false,
+		"application/json":      true,
+		"application/json, */*": true,
+		"text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8": false,
+		"text/html;q=0.5, application/json":                               true,
+		"text/html, application/json":                                     false,
+		"application/json;q=0":                                            false,
+	}
+	for accept, want := range tests {
+		if got := prefersJSON(accept); got != want {
+			t.Errorf("prefersJSON(%q) = %v, want %v", accept, got, want)
+		}
+	}
+}
+
+func TestHandlerErrors(t *testing.T) {
+	if rec := serve(testHandler(), httptest.NewRequest("GET", "/missing", nil)); rec.Code != http.StatusNotFound {
+		t.Errorf("wrapped status error: status = %d, want 404", rec.Code)
+	}
+	rec := serve(testHandler(), httptest.NewRequest("GET", "/broken", nil))
+	if rec.Code != http.StatusInternalServerError || strings.Contains(rec.Body.String(), "boom") {
+		t.Errorf("plain error: status = %d, body = %q", rec.Code, rec.Body.String())
+	}
+}
+
+func TestHandlerFragment(t *testing.T) {
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set(TargetHeader, "#title")
+	rec := serve(testHandler(), req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d", rec.Code)
+	}
+	if body := rec.Body.String(); body != `<h1 class="big" id="title">Hello</h1>` {
+		t.Errorf("fragment body = %q", body)
+	}
+	if vary := rec.Header().Values("Vary"); len(vary) != 2 || vary[1] != TargetHeader {
+		t.Errorf("Vary = %v, fragment responses must vary on %s", vary, TargetHeader)
+	}
+
+	for _, target := range []string{"#nope", ".big"} {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set(TargetHeader, target)
+		if rec := serve(testHandler(), req); rec.Code != http.StatusNotFound {
+			t.Errorf("target %q: status = %d, want 404", target, rec.Code)
+		}
+	}
+}
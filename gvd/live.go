@@ -0,0 +1,196 @@
+// live.go
+package gvd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/TimLai666/go-vdom/dom"
+)
+
+// Clock 提供計時器，讓測試可以注入假時鐘控制心跳
+type Clock interface {
+	NewTicker(d time.Duration) Ticker
+}
+
+// Ticker 對應 time.Ticker 的最小介面
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+}
+
+type systemClock struct{}
+
+func (systemClock) NewTicker(d time.Duration) Ticker { return systemTicker{time.NewTicker(d)} }
+
+type systemTicker struct{ t *time.Ticker }
+
+func (t systemTicker) C() <-chan time.Time { return t.t.C }
+func (t systemTicker) Stop()               { t.t.Stop() }
+
+// DefaultHeartbeat 是 LiveChannel 預設的心跳間隔
+// 定期送出 SSE 註解，避免代理伺服器因連線閒置而將其關閉
+const DefaultHeartbeat = 15 * time.Second
+
+// liveBuffer 是每個連線可暫存的更新數量，超過時該連線會被中斷並由瀏覽器自動重連
+const liveBuffer = 16
+
+// LiveUpdate 是 LiveChannel 以 SSE "swap" 事件送出的資料（JSON）
+type LiveUpdate struct {
+	Target string `json:"target"` // 目標元素的 id（不含 #），例如 "stats"；runtime 以 getElementById 查找，id 不需轉義
+	Swap   string `json:"swap"`   // 替換模式，見 SwapInnerHTML 等常數
+	HTML   string `json:"html"`   // 渲染後的 HTML 片段
+}
+
+// LiveChannel 將 VNode 子樹以 Server-Sent Events 推送給瀏覽器
+// 它本身是 http.Handler；頁面上帶有 data-gvd-live="<端點路徑>" 的元素會讓 runtime
+// 以 EventSource 訂閱該端點，並依更新中的 target 與 swap 替換或就地更新元素
+//
+// 用法：
+//
+//	live := gvd.NewLiveChannel()
+//	http.Handle("/live/stats", live)
+//	// 頁面：Div(Props{"id": "stats", "data-gvd-live": "/live/stats"}, ...)
+//	live.Send("stats", Div(Props{"id": "stats"}, "在線人數：42"))
+//
+// 連線只會收到訂閱之後的更新；需要初始狀態時應直接渲染在頁面中
+type LiveChannel struct {
+	// Heartbeat 是心跳間隔；零值使用 DefaultHeartbeat
+	Heartbeat time.Duration
+	// Clock 用於建立心跳計時器；nil 使用系統時鐘
+	Clock Clock
+
+	mu      sync.Mutex
+	clients map[chan []byte]struct{}
+	seq     uint64
+	closed  bool
+	done    chan struct{}
+}
+
+// NewLiveChannel 創建一個 LiveChannel
+func NewLiveChannel() *LiveChannel {
+	return &LiveChannel{
+		clients: make(map[chan []byte]struct{}),
+		done:    make(chan struct{}),
+	}
+}
+
+// Send 渲染節點並以 morph 模式更新 id 對應的元素
+// 節點本身帶有相同 id 時以其子節點更新目標，否則節點成為目標唯一的子節點
+func (c *LiveChannel) Send(id string, node dom.VNode) {
+	c.SendSwap(id, SwapMorph, node)
+}
+
+// SendSwap 渲染節點並以指定的替換模式更新 id 對應的元素
+func (c *LiveChannel) SendSwap(id, swap string, node dom.VNode) {
+	data, err := json.Marshal(LiveUpdate{Target: id, Swap: swap, HTML: dom.Render(node)})
+	if err != nil {
+		return // LiveUpdate 只包含字串，不會失敗
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return
+	}
+	c.seq++
+	event := []byte(fmt.Sprintf("id: %d\nevent: swap\ndata: %s\n\n", c.seq, data))
+	for ch := range c.clients {
+		select {
+		case ch <- event:
+		default:
+			// 跟不上的連線直接中斷，避免阻塞其他連線；EventSource 會自動重連
+			delete(c.clients, ch)
+			close(ch)
+		}
+	}
+}
+
+// Clients 返回目前連線中的訂閱者數量
+func (c *LiveChannel) Clients() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.clients)
+}
+
+// Close 結束所有連線，之後的 Send 會被忽略，新的請求回應 503
+func (c *LiveChannel) Close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return
+	}
+	c.closed = true
+	close(c.done)
+}
+
+// ServeHTTP 保持 SSE 連線，轉送更新並定期送出心跳，直到客戶端離開或 Close 被呼叫
+func (c *LiveChannel) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	ch := make(chan []byte, liveBuffer)
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+		return
+	}
+	c.clients[ch] = struct{}{}
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		delete(c.clients, ch)
+		c.mu.Unlock()
+	}()
+
+	h := w.Header()
+	h.Set("Content-Type", "text/event-stream")
+	h.Set("Cache-Control", "no-cache")
+	h.Set("Connection", "keep-alive")
+
+	// 初始註解讓瀏覽器立即完成連線
+	fmt.Fprint(w, ": connected\n\n")
+	flusher.Flush()
+
+	ticker := c.clock().NewTicker(c.heartbeat())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-c.done:
+			return
+		case <-ticker.C():
+			fmt.Fprint(w, ": ping\n\n")
+			flusher.Flush()
+		case event, ok := <-ch:
+			if !ok {
+				return
+			}
+			_, _ = w.Write(event)
+			flusher.Flush()
+		}
+	}
+}
+
+func (c *LiveChannel) clock() Clock {
+	if c.Clock == nil {
+		return systemClock{}
+	}
+	return c.Clock
+}
+
+func (c *LiveChannel) heartbeat() time.Duration {
+	if c.Heartbeat <= 0 {
+		return DefaultHeartbeat
+	}
+	return c.Heartbeat
+}
@@ -0,0 +1,305 @@
+package live
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TimLai666/go-vdom/dom"
+)
+
+// counter 是測試用的 live view
+type counter struct {
+	count int
+	name  string
+}
+
+func (c *counter) Render() dom.VNode {
+	return dom.Div(dom.Props{"id": "counter"},
+		dom.H1(nil, fmt.Sprintf("%s: %d", c.name, c.count)),
+		dom.Button(dom.Props{"data-gvd-click": "inc"}, "+"),
+	)
+}
+
+func (c *counter) HandleEvent(ev Event) error {
+	switch ev.Handler {
+	case "inc":
+		c.count++
+	case "rename":
+		c.name = ev.Value
+	case "fail":
+		return fmt.Errorf("bad event")
+	}
+	return nil
+}
+
+// wsClient 是測試用的最小 WebSocket 客戶端
+type wsClient struct {
+	conn net.Conn
+	br   *bufio.Reader
+}
+
+func dial(t *testing.T, srv *httptest.Server, header http.Header) (*wsClient, *http.Response) {
+	t.Helper()
+	conn, err := net.Dial("tcp", strings.TrimPrefix(srv.URL, "http://"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	req, _ := http.NewRequest("GET", srv.URL+"/live", nil)
+	req.Header.Set("Connection", "keep-alive, Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	req.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	if err := req.Write(conn); err != nil {
+		t.Fatal(err)
+	}
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &wsClient{conn: conn, br: br}, resp
+}
+
+func (c *wsClient) writeFrame(t *testing.T, fin bool, opcode byte, payload []byte) {
+	t.Helper()
+	b0 := opcode
+	if fin {
+		b0 |= 0x80
+	}
+	frame := []byte{b0}
+	if len(payload) <= 125 {
+		frame = append(frame, 0x80|byte(len(payload)))
+	} else {
+		frame = append(frame, 0x80|126)
+		frame = binary.BigEndian.AppendUint16(frame, uint16(len(payload)))
+	}
+	mask := []byte{1, 2, 3, 4}
+	frame = append(frame, mask...)
+	for i, b := range payload {
+		frame = append(frame, b^mask[i%4])
+	}
+	if _, err := c.conn.Write(frame); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func (c *wsClient) readFrame(t *testing.T) (byte, []byte) {
+	t.Helper()
+	var head [2]byte
+	if _, err := io.ReadFull(c.br, head[:]); err != nil {
+		t.Fatal(err)
+	}
+	if head[1]&0x80 != 0 {
+		t.Fatal("server frames must not be masked")
+	}
+	n := int(head[1] & 0x7F)
+	if n == 126 {
+		var ext [2]byte
+		io.ReadFull(c.br, ext[:])
+		n = int(binary.BigEndian.Uint16(ext[:]))
+	}
+	payload := make([]byte, n)
+	if _, err := io.ReadFull(c.br, payload); err != nil {
+		t.Fatal(err)
+	}
+	return head[0] & 0x0F, payload
+}
+
+func (c *wsClient) readPatches(t *testing.T) []dom.Patch {
+	t.Helper()
+	op, payload := c.readFrame(t)
+	if op != opText {
+		t.Fatalf("opcode = %#x, want text", op)
+	}
+	var msg message
+	if err := json.Unmarshal(payload, &msg); err != nil {
+		t.Fatal(err)
+	}
+	return msg.Patches
+}
+
+func (c *wsClient) send(t *testing.T, ev Event) {
+	t.Helper()
+	data, _ := json.Marshal(ev)
+	c.writeFrame(t, true, opText, data)
+}
+
+func newServer(t *testing.T, views chan<- *counter) *httptest.Server {
+	srv := httptest.NewServer(Handler(func(r *http.Request) View {
+		v := &counter{name: "count"}
+		if views != nil {
+			views <- v
+		}
+		return v
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestAcceptKey(t *testing.T) {
+	// RFC 6455 第 1.3 節的範例
+	if got := AcceptKey("dGhlIHNhbXBsZSBub25jZQ=="); got != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Errorf("AcceptKey = %q", got)
+	}
+}
+
+func TestSessionOverWebSocket(t *testing.T) {
+	srv := newServer(t, nil)
+	c, resp := dial(t, srv, nil)
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("handshake status = %d", resp.StatusCode)
+	}
+	if got := resp.Header.Get("Sec-WebSocket-Accept"); got != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Errorf("Sec-WebSocket-Accept = %q", got)
+	}
+
+	initial := c.readPatches(t)
+	want := `<div id="counter"><h1>count: 0</h1><button data-gvd-click="inc">+</button></div>`
+	if len(initial) != 1 || initial[0].Op != dom.PatchReplace || initial[0].HTML != want {
+		t.Fatalf("initial patches = %+v", initial)
+	}
+
+	c.send(t, Event{Handler: "inc", Type: "click"})
+	got := c.readPatches(t)
+	if wantPatches := []dom.Patch{{Op: dom.PatchHTML, Path: []int{0}, HTML: "count: 1"}}; !reflect.DeepEqual(got, wantPatches) {
+		t.Errorf("click patches = %+v, want %+v", got, wantPatches)
+	}
+
+	// 分段訊息與穿插其中的 ping
+	data, _ := json.Marshal(Event{Handler: "rename", Type: "input", Value: "clicks"})
+	c.writeFrame(t, false, opText, data[:10])
+	c.writeFrame(t, true, opPing, []byte("hi"))
+	c.writeFrame(t, true, opContinuation, data[10:])
+	if op, payload := c.readFrame(t); op != opPong || string(payload) != "hi" {
+		t.Errorf("ping reply = %#x %q", op, payload)
+	}
+	if got := c.readPatches(t); len(got) != 1 || got[0].HTML != "clicks: 1" {
+		t.Errorf("input patches = %+v", got)
+	}
+
+	c.writeFrame(t, true, opClose, []byte{0x03, 0xE8})
+	if op, _ := c.readFrame(t); op != opClose {
+		t.Errorf("expected close reply, got %#x", op)
+	}
+}
+
+func TestSessionHandlerError(t *testing.T) {
+	views := make(chan *counter, 1)
+	srv := newServer(t, views)
+	c, _ := dial(t, srv, nil)
+	c.readPatches(t)
+	v := <-views
+
+	c.send(t, Event{Handler: "inc", Type: "click"})
+	c.readPatches(t)
+	if v.count != 1 {
+		t.Fatalf("count = %d", v.count)
+	}
+
+	c.send(t, Event{Handler: "fail", Type: "click"})
+	if op, _ := c.readFrame(t); op != opClose {
+		t.Errorf("handler error should close the connection, got %#x", op)
+	}
+}
+
+func TestUpgradeRejectsBadRequests(t *testing.T) {
+	srv := newServer(t, nil)
+
+	_, resp := dial(t, srv, http.Header{"Origin": {"http://evil.example"}})
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("cross-origin: status = %d, want 403", resp.StatusCode)
+	}
+
+	resp, err := http.Get(srv.URL + "/live")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("plain GET: status = %d, want 400", resp.StatusCode)
+	}
+}
+
+// pipeTransport 以 channel 實作 Transport，用於不經過 WebSocket 的 session 測試
+type pipeTransport struct {
+	in  chan []byte
+	out chan []byte
+}
+
+func (p *pipeTransport) ReadMessage() ([]byte, error) {
+	data, ok := <-p.in
+	if !ok {
+		return nil, io.EOF
+	}
+	return data, nil
+}
+
+func (p *pipeTransport) WriteMessage(data []byte) error { p.out <- data; return nil }
+func (p *pipeTransport) Close() error                   { return nil }
+
+type ticker struct {
+	counter
+	session *Session
+}
+
+func (v *ticker) Mount(s *Session) { v.session = s }
+
+func TestSessionTransportAndMount(t *testing.T) {
+	tr := &pipeTransport{in: make(chan []byte), out: make(chan []byte, 4)}
+	v := &ticker{counter: counter{name: "ticks"}}
+	s := NewSession(tr, v)
+
+	errc := make(chan error, 1)
+	go func() { errc <- s.Run() }()
+	<-tr.out // 初始內容
+
+	if v.session != s {
+		t.Fatal("Mount was not called with the session")
+	}
+	if err := s.Update(func() { v.count = 5 }); err != nil {
+		t.Fatal(err)
+	}
+	var msg message
+	json.Unmarshal(<-tr.out, &msg)
+	if len(msg.Patches) != 1 || msg.Patches[0].HTML != "ticks: 5" {
+		t.Errorf("pushed patches = %+v", msg.Patches)
+	}
+
+	// 沒有變化時不送出訊息
+	if err := s.Update(nil); err != nil || len(tr.out) != 0 {
+		t.Errorf("no-op update sent %d messages (err %v)", len(tr.out), err)
+	}
+
+	close(tr.in)
+	if err := <-errc; err != nil {
+		t.Errorf("Run() = %v, want nil on EOF", err)
+	}
+	select {
+	case <-s.Done():
+	default:
+		t.Error("Done should be closed after Run returns")
+	}
+}
+
+func TestMount(t *testing.T) {
+	got := dom.Render(Mount("/live/counter", &counter{name: "n"}))
+	if !strings.HasPrefix(got, `<div data-gvd-view="/live/counter"><div id="counter">`) {
+		t.Errorf("Mount = %s", got)
+	}
+}
@@ -0,0 +1,177 @@
+// session.go
+package live
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/TimLai666/go-vdom/dom"
+)
+
+// Transport 是 session 與瀏覽器之間的訊息通道
+// *Conn 以 WebSocket 實作；測試或其他協定可提供自己的實作
+type Transport interface {
+	// ReadMessage 讀取下一則訊息；對方正常關閉時返回 io.EOF
+	ReadMessage() ([]byte, error)
+	// WriteMessage 送出一則訊息，必須可被多個 goroutine 同時呼叫
+	WriteMessage(data []byte) error
+	Close() error
+}
+
+// Event 是瀏覽器送來的事件
+// Handler 是元素上 data-gvd-click / data-gvd-input / data-gvd-change / data-gvd-submit 的值
+type Event struct {
+	Handler string            `json:"handler"`
+	Type    string            `json:"type"`            // click、input、change 或 submit
+	Value   string            `json:"value,omitempty"` // 觸發元素的 value（input、change）
+	Form    map[string]string `json:"form,omitempty"`  // 表單欄位（submit）
+}
+
+// View 是一個 live view 的伺服器端狀態
+// 每個瀏覽器連線擁有獨立的 View；Render 與 HandleEvent 由 session 依序呼叫，不會同時執行
+type View interface {
+	Render() dom.VNode
+	HandleEvent(ev Event) error
+}
+
+// Mounter 是可選介面；View 實作時會在 session 開始時收到 session，
+// 可保存起來在背景 goroutine 中呼叫 Update 主動推送更新
+type Mounter interface {
+	Mount(s *Session)
+}
+
+// message 是伺服器送給瀏覽器的訊息
+type message struct {
+	Patches []dom.Patch `json:"patches"`
+}
+
+// Session 連接一個 View 與一個 Transport
+// 每次事件處理或 Update 後重新渲染，與上一次的樹比對並只送出補丁
+type Session struct {
+	view      View
+	transport Transport
+
+	mu      sync.Mutex
+	tree    dom.VNode
+	mounted bool
+	done    chan struct{}
+}
+
+// NewSession 創建一個 session；呼叫 Run 後開始運作
+func NewSession(t Transport, v View) *Session {
+	return &Session{view: v, transport: t, done: make(chan struct{})}
+}
+
+// Done 在 session 結束（連線中斷）時關閉，背景 goroutine 應以此停止
+func (s *Session) Done() <-chan struct{} {
+	return s.done
+}
+
+// Update 在 session 鎖內執行 mutate（可為 nil）後重新渲染並送出補丁
+// 背景 goroutine 修改 View 狀態時應透過 mutate 進行，以免與事件處理同時執行
+//
+// 用法：
+//
+//	go func() {
+//	    for range ticker.C {
+//	        s.Update(func() { v.now = time.Now() })
+//	    }
+//	}()
+func (s *Session) Update(mutate func()) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if mutate != nil {
+		mutate()
+	}
+	return s.render()
+}
+
+// render 重新渲染並送出補丁；呼叫者必須持有 s.mu
+// 第一次渲染送出取代整個容器內容的補丁，瀏覽器以此取代初始的靜態 HTML
+func (s *Session) render() error {
+	tree := s.view.Render()
+	var patches []dom.Patch
+	if s.mounted {
+		patches = dom.Diff(s.tree, tree)
+	} else {
+		patches = []dom.Patch{{Op: dom.PatchReplace, Path: []int{}, HTML: dom.Render(tree)}}
+		s.mounted = true
+	}
+	s.tree = tree
+	if len(patches) == 0 {
+		return nil
+	}
+
+	data, err := json.Marshal(message{Patches: patches})
+	if err != nil {
+		return fmt.Errorf("live: encode patches: %w", err)
+	}
+	return s.transport.WriteMessage(data)
+}
+
+// Run 送出初始內容並處理事件，直到連線關閉或 View 返回錯誤
+// 對方正常關閉時返回 nil
+func (s *Session) Run() error {
+	defer close(s.done)
+
+	if m, ok := s.view.(Mounter); ok {
+		m.Mount(s)
+	}
+	if err := s.Update(nil); err != nil {
+		return err
+	}
+
+	for {
+		data, err := s.transport.ReadMessage()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		var ev Event
+		if err := json.Unmarshal(data, &ev); err != nil {
+			return fmt.Errorf("live: invalid event: %w", err)
+		}
+
+		s.mu.Lock()
+		err = s.view.HandleEvent(ev)
+		if err == nil {
+			err = s.render()
+		}
+		s.mu.Unlock()
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// Handler 返回 live view 的 WebSocket 端點；每個連線以 newView 建立獨立的狀態
+//
+// 用法：
+//
+//	http.Handle("/live/counter", live.Handler(func(r *http.Request) live.View {
+//	    return &Counter{}
+//	}))
+//	// 頁面中：live.Mount("/live/counter", &Counter{})
+func Handler(newView func(r *http.Request) View) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := Upgrade(w, r)
+		if err != nil {
+			return // Upgrade 已寫入錯誤回應
+		}
+		defer conn.Close()
+		_ = NewSession(conn, newView(r)).Run()
+	})
+}
+
+// Mount 返回 live view 的容器，內含 View 的初始渲染結果
+// runtime 會連線到 endpoint，並以 session 送來的內容取代初始 HTML
+func Mount(endpoint string, v View) dom.VNode {
+	return dom.Div(dom.Props{"data-gvd-view": endpoint}, v.Render())
+}
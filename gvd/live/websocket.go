@@ -0,0 +1,249 @@
+// websocket.go
+package live
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// websocketGUID 是 RFC 6455 握手使用的固定 GUID
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// MaxMessageSize 是單一訊息（含分段）的最大位元組數
+const MaxMessageSize = 1 << 20
+
+// WebSocket 操作碼
+const (
+	opContinuation = 0x0
+	opText         = 0x1
+	opBinary       = 0x2
+	opClose        = 0x8
+	opPing         = 0x9
+	opPong         = 0xA
+)
+
+// ErrMessageTooLarge 表示收到的訊息超過 MaxMessageSize
+var ErrMessageTooLarge = errors.New("live: websocket message too large")
+
+// Conn 是伺服器端的 WebSocket 連線，只實作 live view 所需的部分：
+// 收發文字訊息、分段訊息重組、回應 ping 與關閉握手；不支援擴充（如壓縮）
+type Conn struct {
+	conn net.Conn
+	br   *bufio.Reader
+
+	wmu       sync.Mutex
+	closeSent bool
+}
+
+// Upgrade 完成 WebSocket 握手並接管底層連線
+// 請求帶有 Origin 時必須與 Host 相同，避免其他網站冒用使用者的連線（CSWSH）；
+// 握手失敗時已寫入錯誤回應
+func Upgrade(w http.ResponseWriter, r *http.Request) (*Conn, error) {
+	if err := checkHandshake(r); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return nil, err
+	}
+	if origin := r.Header.Get("Origin"); origin != "" {
+		u, err := url.Parse(origin)
+		if err != nil || !strings.EqualFold(u.Host, r.Host) {
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+			return nil, fmt.Errorf("live: cross-origin websocket request from %q", origin)
+		}
+	}
+
+	hj, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "websocket unsupported", http.StatusInternalServerError)
+		return nil, errors.New("live: response writer cannot be hijacked")
+	}
+	conn, rw, err := hj.Hijack()
+	if err != nil {
+		return nil, fmt.Errorf("live: hijack: %w", err)
+	}
+
+	fmt.Fprintf(rw, "HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Accept: %s\r\n\r\n",
+		AcceptKey(r.Header.Get("Sec-WebSocket-Key")))
+	if err := rw.Flush(); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("live: handshake: %w", err)
+	}
+	return &Conn{conn: conn, br: rw.Reader}, nil
+}
+
+// AcceptKey 依客戶端的 Sec-WebSocket-Key 計算 Sec-WebSocket-Accept
+func AcceptKey(key string) string {
+	sum := sha1.Sum([]byte(key + websocketGUID))
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+func checkHandshake(r *http.Request) error {
+	switch {
+	case r.Method != http.MethodGet:
+		return errors.New("live: websocket handshake requires GET")
+	case !headerContainsToken(r.Header, "Connection", "upgrade"):
+		return errors.New("live: missing Connection: Upgrade")
+	case !headerContainsToken(r.Header, "Upgrade", "websocket"):
+		return errors.New("live: missing Upgrade: websocket")
+	case r.Header.Get("Sec-WebSocket-Version") != "13":
+		return errors.New("live: unsupported websocket version")
+	case r.Header.Get("Sec-WebSocket-Key") == "":
+		return errors.New("live: missing Sec-WebSocket-Key")
+	}
+	return nil
+}
+
+// headerContainsToken 判斷以逗號分隔的標頭是否包含 token（不分大小寫）
+func headerContainsToken(h http.Header, name, token string) bool {
+	for _, v := range h.Values(name) {
+		for _, t := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ReadMessage 讀取下一個完整的文字或二進位訊息
+// 期間收到的 ping 會自動回應 pong；收到關閉訊框時回應關閉並返回 io.EOF
+func (c *Conn) ReadMessage() ([]byte, error) {
+	var message []byte
+	inMessage := false
+	for {
+		fin, opcode, payload, err := c.readFrame()
+		if err != nil {
+			return nil, err
+		}
+		switch opcode {
+		case opPing:
+			if err := c.writeFrame(opPong, payload); err != nil {
+				return nil, err
+			}
+		case opPong:
+		case opClose:
+			code := payload
+			if len(code) > 2 {
+				code = code[:2]
+			}
+			_ = c.writeFrame(opClose, code)
+			return nil, io.EOF
+		case opText, opBinary, opContinuation:
+			if (opcode == opContinuation) != inMessage {
+				return nil, errors.New("live: unexpected websocket continuation frame")
+			}
+			if len(message)+len(payload) > MaxMessageSize {
+				return nil, ErrMessageTooLarge
+			}
+			message = append(message, payload...)
+			inMessage = true
+			if fin {
+				return message, nil
+			}
+		default:
+			return nil, fmt.Errorf("live: unknown websocket opcode %#x", opcode)
+		}
+	}
+}
+
+// readFrame 讀取並解除遮罩一個訊框；客戶端送來的訊框必須有遮罩
+func (c *Conn) readFrame() (fin bool, opcode byte, payload []byte, err error) {
+	var head [2]byte
+	if _, err = io.ReadFull(c.br, head[:]); err != nil {
+		return
+	}
+	fin = head[0]&0x80 != 0
+	opcode = head[0] & 0x0F
+	if head[0]&0x70 != 0 {
+		err = errors.New("live: websocket extensions are not supported")
+		return
+	}
+	if head[1]&0x80 == 0 {
+		err = errors.New("live: client websocket frames must be masked")
+		return
+	}
+
+	length := uint64(head[1] & 0x7F)
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err = io.ReadFull(c.br, ext[:]); err != nil {
+			return
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err = io.ReadFull(c.br, ext[:]); err != nil {
+			return
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+	if opcode >= opClose && (!fin || length > 125) {
+		err = errors.New("live: invalid websocket control frame")
+		return
+	}
+	if length > MaxMessageSize {
+		err = ErrMessageTooLarge
+		return
+	}
+
+	var mask [4]byte
+	if _, err = io.ReadFull(c.br, mask[:]); err != nil {
+		return
+	}
+	payload = make([]byte, length)
+	if _, err = io.ReadFull(c.br, payload); err != nil {
+		return
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+	return
+}
+
+// WriteMessage 以單一文字訊框送出訊息，可被多個 goroutine 同時呼叫
+func (c *Conn) WriteMessage(data []byte) error {
+	return c.writeFrame(opText, data)
+}
+
+// writeFrame 寫入一個未遮罩的訊框（伺服器送出的訊框不得遮罩）
+func (c *Conn) writeFrame(opcode byte, payload []byte) error {
+	header := []byte{0x80 | opcode}
+	switch n := len(payload); {
+	case n <= 125:
+		header = append(header, byte(n))
+	case n <= 0xFFFF:
+		header = append(header, 126)
+		header = binary.BigEndian.AppendUint16(header, uint16(n))
+	default:
+		header = append(header, 127)
+		header = binary.BigEndian.AppendUint64(header, uint64(n))
+	}
+
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+	// 關閉訊框只送一次，之後不得再送出任何訊框
+	if c.closeSent {
+		return net.ErrClosed
+	}
+	c.closeSent = opcode == opClose
+	if _, err := c.conn.Write(append(header, payload...)); err != nil {
+		return fmt.Errorf("live: websocket write: %w", err)
+	}
+	return nil
+}
+
+// Close 送出正常關閉訊框並關閉底層連線
+func (c *Conn) Close() error {
+	_ = c.writeFrame(opClose, []byte{0x03, 0xE8}) // 1000 Normal Closure
+	return c.conn.Close()
+}
@@ -0,0 +1,178 @@
+package gvd
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TimLai666/go-vdom/dom"
+)
+
+// fakeClock 的計時器只在測試呼叫 tick 時觸發
+type fakeClock struct {
+	ticks   chan time.Time
+	created chan time.Duration
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{ticks: make(chan time.Time), created: make(chan time.Duration, 1)}
+}
+
+func (c *fakeClock) NewTicker(d time.Duration) Ticker {
+	c.created <- d
+	return fakeTicker{c.ticks}
+}
+
+func (c *fakeClock) tick() { c.ticks <- time.Time{} }
+
+type fakeTicker struct{ c chan time.Time }
+
+func (t fakeTicker) C() <-chan time.Time { return t.c }
+func (t fakeTicker) Stop()               {}
+
+// streamWriter 透過 io.Pipe 讓測試逐行讀取 SSE 回應，不經過網路
+type streamWriter struct {
+	header http.Header
+	pw     *io.PipeWriter
+}
+
+func (w *streamWriter) Header() http.Header         { return w.header }
+func (w *streamWriter) Write(b []byte) (int, error) { return w.pw.Write(b) }
+func (w *streamWriter) WriteHeader(int)             {}
+func (w *streamWriter) Flush()                      {}
+
+// subscribe 在背景執行 ServeHTTP，返回事件讀取器與結束連線的函數
+func subscribe(t *testing.T, c *LiveChannel) (*bufio.Reader, *streamWriter, context.CancelFunc, <-chan struct{}) {
+	t.Helper()
+	pr, pw := io.Pipe()
+	w := &streamWriter{header: http.Header{}, pw: pw}
+	ctx, cancel := context.WithCancel(context.Background())
+	req := httptest.NewRequest("GET", "/live", nil).WithContext(ctx)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		c.ServeHTTP(w, req)
+		pw.Close()
+	}()
+
+	reader := bufio.NewReader(pr)
+	if line, _ := reader.ReadString('\n'); line != ": connected\n" {
+		t.Fatalf("first line = %q", line)
+	}
+	reader.ReadString('\n')
+	return reader, w, cancel, done
+}
+
+// readEvent 讀取下一個以空行結束的 SSE 區塊
+func readEvent(t *testing.T, r *bufio.Reader) []string {
+	t.Helper()
+	var lines []string
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("stream ended: %v", err)
+		}
+		line = strings.TrimSuffix(line, "\n")
+		if line == "" {
+			return lines
+		}
+		lines = append(lines, line)
+	}
+}
+
+func TestLiveChannelStreamsUpdates(t *testing.T) {
+	clock := newFakeClock()
+	c := NewLiveChannel()
+	c.Clock = clock
+	c.Heartbeat = 5 * time.Second
+
+	reader, w, cancel, done := subscribe(t, c)
+	if got := <-clock.created; got != 5*time.Second {
+		t.Errorf("heartbeat interval = %v", got)
+	}
+	if ct := w.header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	if c.Clients() != 1 {
+		t.Fatalf("clients = %d", c.Clients())
+	}
+
+	c.Send("stats", dom.Div(dom.Props{"id": "stats"}, "42 <online>"))
+	ev := readEvent(t, reader)
+	if len(ev) != 3 || ev[0] != "id: 1" || ev[1] != "event: swap" || !strings.HasPrefix(ev[2], "data: ") {
+		t.Fatalf("unexpected event: %q", ev)
+	}
+	var update LiveUpdate
+	if err := json.Unmarshal([]byte(strings.TrimPrefix(ev[2], "data: ")), &update); err != nil {
+		t.Fatal(err)
+	}
+	want := LiveUpdate{Target: "stats", Swap: SwapMorph, HTML: `<div id="stats">42 <online></div>`}
+	if update != want {
+		t.Errorf("update = %+v, want %+v", update, want)
+	}
+
+	go clock.tick()
+	if ev := readEvent(t, reader); len(ev) != 1 || ev[0] != ": ping" {
+		t.Errorf("heartbeat = %q", ev)
+	}
+
+	// id 原樣送出，不組成 CSS 選擇器
+	c.SendSwap("log.2:a", SwapBeforeEnd, dom.Li(nil, "line"))
+	if ev := readEvent(t, reader); ev[0] != "id: 2" || !strings.Contains(ev[2], `"target":"log.2:a","swap":"beforeend"`) {
+		t.Errorf("second event = %q", ev)
+	}
+
+	cancel()
+	<-done
+	if c.Clients() != 0 {
+		t.Errorf("client not removed after disconnect")
+	}
+}
+
+func TestLiveChannelDropsSlowClients(t *testing.T) {
+	c := NewLiveChannel()
+	c.Clock = newFakeClock()
+
+	// 直接註冊一個不讀取的連線，模擬跟不上的客戶端
+	ch := make(chan []byte, liveBuffer)
+	c.clients[ch] = struct{}{}
+	for i := 0; i <= liveBuffer; i++ {
+		c.Send("x", dom.Span(nil, "tick"))
+	}
+	if c.Clients() != 0 {
+		t.Fatalf("slow client should be dropped")
+	}
+	n := 0
+	for range ch {
+		n++
+	}
+	if n != liveBuffer {
+		t.Errorf("buffered events = %d, want %d", n, liveBuffer)
+	}
+}
+
+func TestLiveChannelClose(t *testing.T) {
+	c := NewLiveChannel()
+	c.Clock = newFakeClock()
+
+	reader, _, cancel, done := subscribe(t, c)
+	defer cancel()
+	c.Close()
+	<-done
+	if _, err := reader.ReadString('\n'); err != io.EOF {
+		t.Errorf("stream should end after Close, got %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	c.ServeHTTP(rec, httptest.NewRequest("GET", "/live", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("status after Close = %d, want 503", rec.Code)
+	}
+}
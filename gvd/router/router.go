@@ -0,0 +1,246 @@
+// router.go
+package router
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/TimLai666/go-vdom/dom"
+	"github.com/TimLai666/go-vdom/gvd"
+)
+
+// Context 是頁面與佈局渲染時可取得的請求資訊
+type Context struct {
+	Request *http.Request
+}
+
+// Param 返回路徑參數的值，對應 ServeMux 模式中的 {name} 萬用字元
+// 例如模式 "GET /posts/{id}" 搭配請求 "/posts/42"，Param("id") 返回 "42"
+func (c *Context) Param(name string) string {
+	return c.Request.PathValue(name)
+}
+
+// Context 返回請求的 context.Context
+func (c *Context) Context() context.Context {
+	return c.Request.Context()
+}
+
+// Layout 以頁面內容作為 children，包上共用的結構（header、nav、footer 等）
+type Layout func(ctx *Context, children dom.VNode) dom.VNode
+
+// Page 產生頁面內容；返回錯誤時會改為渲染錯誤頁
+type Page func(ctx *Context) (dom.VNode, error)
+
+// StatusError 是帶有 HTTP 狀態碼的錯誤，頁面可返回它來控制回應狀態
+type StatusError struct {
+	Code int
+	Err  error
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%d %s: %v", e.Code, http.StatusText(e.Code), e.Err)
+}
+
+func (e *StatusError) Unwrap() error {
+	return e.Err
+}
+
+// StatusCode 返回 HTTP 狀態碼，使 gvd.Handler 也能辨識此錯誤
+func (e *StatusError) StatusCode() int {
+	return e.Code
+}
+
+// Errorf 創建一個帶有狀態碼的錯誤
+// 用法：return VNode{}, router.Errorf(http.StatusForbidden, "post %s is private", id)
+func Errorf(code int, format string, args ...any) error {
+	return &StatusError{Code: code, Err: fmt.Errorf(format, args...)}
+}
+
+// ErrNotFound 讓頁面渲染 404 頁面，例如找不到路徑參數對應的資料時
+var ErrNotFound = &StatusError{Code: http.StatusNotFound, Err: errors.New("page not found")}
+
+// Router 是建立在 http.ServeMux 之上的頁面路由
+//   - 頁面模式使用 Go 1.22 ServeMux 語法，如 "GET /posts/{id}"、"/docs/{path...}"
+//   - 佈局依路徑前綴巢狀套用，外層（前綴較短）包住內層
+//   - 找不到頁面與頁面錯誤都會渲染為 VNode，並同樣套用佈局
+//   - 回應透過 gvd.Write 輸出，因此同樣支援 ETag、304、HEAD 與 JSON 協商
+type Router struct {
+	mux       *http.ServeMux
+	layouts   map[string]Layout
+	notFound  func(ctx *Context) dom.VNode
+	errorPage func(ctx *Context, err error) dom.VNode
+}
+
+// New 創建一個新的 Router
+func New() *Router {
+	return &Router{
+		mux:       http.NewServeMux(),
+		layouts:   make(map[string]Layout),
+		notFound:  defaultNotFound,
+		errorPage: defaultError,
+	}
+}
+
+// Layout 為路徑前綴註冊佈局
+// 前綴以路徑段為單位匹配："/blog" 會套用到 "/blog" 與 "/blog/hello"，但不包括 "/blogger"
+// "/" 的佈局套用到所有頁面
+func (r *Router) Layout(prefix string, layout Layout) {
+	r.layouts[normalizePrefix(prefix)] = layout
+}
+
+// Page 註冊頁面
+// 模式 "/" 與 "GET /" 只匹配根路徑（等同 "/{$}"），其餘模式沿用 ServeMux 的規則
+func (r *Router) Page(pattern string, page Page) {
+	r.mux.HandleFunc(rootOnly(pattern), func(w http.ResponseWriter, req *http.Request) {
+		ctx := &Context{Request: req}
+		node, err := page(ctx)
+		if err != nil {
+			r.writeError(w, ctx, err)
+			return
+		}
+		r.write(w, ctx, http.StatusOK, node)
+	})
+}
+
+// Handle 註冊一般的 http.Handler（例如 API 端點），不套用佈局
+func (r *Router) Handle(pattern string, handler http.Handler) {
+	r.mux.Handle(pattern, handler)
+}
+
+// HandleFunc 註冊一般的 handler 函數，不套用佈局
+func (r *Router) HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request)) {
+	r.mux.HandleFunc(pattern, handler)
+}
+
+// NotFound 設定 404 頁面
+func (r *Router) NotFound(page func(ctx *Context) dom.VNode) {
+	r.notFound = page
+}
+
+// Error 設定錯誤頁面；狀態碼來自 StatusError，其他錯誤視為 500
+func (r *Router) Error(page func(ctx *Context, err error) dom.VNode) {
+	r.errorPage = page
+}
+
+// ServeHTTP 實作 http.Handler
+// 沒有任何模式匹配的請求渲染 NotFound 頁面；路徑匹配但方法不符時仍由 ServeMux 回應 405 與 Allow
+func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	h, pattern := r.mux.Handler(req)
+	if pattern != "" {
+		r.mux.ServeHTTP(w, req)
+		return
+	}
+	nw := &notFoundWriter{ResponseWriter: w}
+	h.ServeHTTP(nw, req)
+	if nw.notFound {
+		ctx := &Context{Request: req}
+		r.write(w, ctx, http.StatusNotFound, r.notFound(ctx))
+	}
+}
+
+// notFoundWriter 攔截 ServeMux 的 404 回應，其他回應（如 405）原樣寫出
+type notFoundWriter struct {
+	http.ResponseWriter
+	notFound bool
+}
+
+func (w *notFoundWriter) WriteHeader(code int) {
+	if code == http.StatusNotFound {
+		w.notFound = true
+		return
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *notFoundWriter) Write(b []byte) (int, error) {
+	if w.notFound {
+		return len(b), nil
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Wrap 以符合請求路徑的佈局由內而外包裝節點
+func (r *Router) Wrap(ctx *Context, node dom.VNode) dom.VNode {
+	for _, prefix := range r.matchingLayouts(ctx.Request.URL.Path) {
+		node = r.layouts[prefix](ctx, node)
+	}
+	return node
+}
+
+// matchingLayouts 返回符合路徑的佈局前綴，由最內層（最長）到最外層
+func (r *Router) matchingLayouts(urlPath string) []string {
+	var matched []string
+	for prefix := range r.layouts {
+		if prefix == "/" || urlPath == prefix || strings.HasPrefix(urlPath, prefix+"/") {
+			matched = append(matched, prefix)
+		}
+	}
+	sort.Slice(matched, func(i, j int) bool { return len(matched[i]) > len(matched[j]) })
+	return matched
+}
+
+func (r *Router) writeError(w http.ResponseWriter, ctx *Context, err error) {
+	var se *StatusError
+	if errors.As(err, &se) && se.Code == http.StatusNotFound {
+		r.write(w, ctx, http.StatusNotFound, r.notFound(ctx))
+		return
+	}
+	code := http.StatusInternalServerError
+	if se != nil {
+		code = se.Code
+	}
+	r.write(w, ctx, code, r.errorPage(ctx, err))
+}
+
+func (r *Router) write(w http.ResponseWriter, ctx *Context, code int, node dom.VNode) {
+	gvd.Write(w, ctx.Request, code, r.Wrap(ctx, node))
+}
+
+func defaultNotFound(ctx *Context) dom.VNode {
+	// dom.Render 不轉義文字內容，路徑來自請求，必須先轉義
+	return dom.Div(nil,
+		dom.H1(nil, "404 Not Found"),
+		dom.P(nil, "找不到頁面："+html.EscapeString(ctx.Request.URL.Path)),
+	)
+}
+
+func defaultError(ctx *Context, err error) dom.VNode {
+	code := http.StatusInternalServerError
+	var se *StatusError
+	if errors.As(err, &se) {
+		code = se.Code
+	}
+	// 不把錯誤內容輸出給瀏覽器，避免洩漏內部資訊
+	return dom.Div(nil, dom.H1(nil, fmt.Sprintf("%d %s", code, http.StatusText(code))))
+}
+
+// normalizePrefix 將佈局前綴整理為 "/" 或不帶結尾斜線的 "/a/b"
+func normalizePrefix(prefix string) string {
+	if !strings.HasPrefix(prefix, "/") {
+		prefix = "/" + prefix
+	}
+	if prefix != "/" {
+		prefix = strings.TrimSuffix(prefix, "/")
+	}
+	return prefix
+}
+
+// rootOnly 將 "/" 模式轉為只匹配根路徑的 "/{$}"
+func rootOnly(pattern string) string {
+	method, path, hasMethod := strings.Cut(pattern, " ")
+	if !hasMethod {
+		path, method = method, ""
+	}
+	if path != "/" {
+		return pattern
+	}
+	if method == "" {
+		return "/{$}"
+	}
+	return method + " /{$}"
+}
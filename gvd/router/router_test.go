@@ -0,0 +1,170 @@
+package router
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/TimLai666/go-vdom/dom"
+)
+
+func newTestRouter() *Router {
+	r := New()
+	r.Layout("/", func(ctx *Context, children dom.VNode) dom.VNode {
+		return dom.Html(nil, dom.Body(nil, dom.Nav(nil, "site-nav"), children))
+	})
+	r.Layout("/blog/", func(ctx *Context, children dom.VNode) dom.VNode {
+		return dom.Div(dom.Props{"class": "blog"}, children)
+	})
+	r.Page("GET /", func(ctx *Context) (dom.VNode, error) {
+		return dom.H1(nil, "home"), nil
+	})
+	r.Page("GET /blog/{slug}", func(ctx *Context) (dom.VNode, error) {
+		switch slug := ctx.Param("slug"); slug {
+		case "missing":
+			return dom.VNode{}, ErrNotFound
+		case "secret":
+			return dom.VNode{}, Errorf(http.StatusForbidden, "post %s is private", slug)
+		case "broken":
+			return dom.VNode{}, errors.New("database down")
+		default:
+			return dom.Article(nil, "post:"+slug), nil
+		}
+	})
+	r.HandleFunc("GET /api/ping", func(w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte("pong"))
+	})
+	return r
+}
+
+func get(t *testing.T, h http.Handler, method, path string) *httptest.ResponseRecorder {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+	return rec
+}
+
+func TestRouterNestedLayouts(t *testing.T) {
+	r := newTestRouter()
+
+	rec := get(t, r, "GET", "/blog/hello")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d", rec.Code)
+	}
+	body := rec.Body.String()
+	want := `<!DOCTYPE html>` + "\n" + `<html><body><nav>site-nav</nav><div class="blog"><article>post:hello</article></div></body></html>`
+	if body != want {
+		t.Errorf("body =\n%s\nwant\n%s", body, want)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "text/html; charset=utf-8" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+
+	home := get(t, r, "GET", "/").Body.String()
+	if !strings.Contains(home, "<h1>home</h1>") || strings.Contains(home, `class="blog"`) {
+		t.Errorf("root page should only use the root layout: %s", home)
+	}
+}
+
+func TestRouterNotFound(t *testing.T) {
+	r := newTestRouter()
+	r.NotFound(func(ctx *Context) dom.VNode {
+		return dom.P(nil, "nothing at "+ctx.Request.URL.Path)
+	})
+
+	for _, path := range []string{"/nope", "/blog/missing", "/bloggers"} {
+		rec := get(t, r, "GET", path)
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("%s: status = %d, want 404", path, rec.Code)
+		}
+		body := rec.Body.String()
+		if !strings.Contains(body, "<p>nothing at "+path+"</p>") || !strings.Contains(body, "site-nav") {
+			t.Errorf("%s: 404 page should be rendered inside layouts: %s", path, body)
+		}
+		if strings.Contains(body, `class="blog"`) != strings.HasPrefix(path, "/blog/") {
+			t.Errorf("%s: blog layout applied incorrectly: %s", path, body)
+		}
+	}
+}
+
+func TestRouterMethodNotAllowed(t *testing.T) {
+	r := newTestRouter()
+
+	rec := get(t, r, "POST", "/blog/hello")
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("status = %d, want 405", rec.Code)
+	}
+	if allow := rec.Header().Get("Allow"); !strings.Contains(allow, "GET") {
+		t.Errorf("Allow = %q, want GET", allow)
+	}
+}
+
+func TestRouterCatchAllHandler(t *testing.T) {
+	r := newTestRouter()
+	r.HandleFunc("/", func(w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte("fallback"))
+	})
+
+	if body := get(t, r, "GET", "/nope").Body.String(); body != "fallback" {
+		t.Errorf("catch-all handler should replace the 404 page, got %q", body)
+	}
+	if body := get(t, r, "GET", "/").Body.String(); !strings.Contains(body, "<h1>home</h1>") {
+		t.Errorf("root page should still match: %s", body)
+	}
+}
+
+func TestRouterDefaultNotFoundEscapesPath(t *testing.T) {
+	r := newTestRouter()
+
+	rec := get(t, r, "GET", "/%3Cimg%20src=x%20onerror=alert(1)%3E")
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want 404", rec.Code)
+	}
+	body := rec.Body.String()
+	if strings.Contains(body, "<img") {
+		t.Errorf("path markup should not be rendered: %s", body)
+	}
+	if !strings.Contains(body, "&lt;img src=x onerror=alert(1)&gt;") {
+		t.Errorf("path should be escaped: %s", body)
+	}
+}
+
+func TestRouterErrors(t *testing.T) {
+	r := newTestRouter()
+
+	rec := get(t, r, "GET", "/blog/secret")
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("status = %d, want 403", rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), "403 Forbidden") {
+		t.Errorf("default error page missing status: %s", rec.Body.String())
+	}
+
+	var gotErr error
+	r.Error(func(ctx *Context, err error) dom.VNode {
+		gotErr = err
+		return dom.P(nil, "oops")
+	})
+	rec = get(t, r, "GET", "/blog/broken")
+	if rec.Code != http.StatusInternalServerError || !strings.Contains(rec.Body.String(), "<p>oops</p>") {
+		t.Errorf("status = %d body = %s", rec.Code, rec.Body.String())
+	}
+	if gotErr == nil || gotErr.Error() != "database down" {
+		t.Errorf("error page received %v", gotErr)
+	}
+}
+
+func TestRouterHandlersAndHead(t *testing.T) {
+	r := newTestRouter()
+
+	if body := get(t, r, "GET", "/api/ping").Body.String(); body != "pong" {
+		t.Errorf("plain handler body = %q", body)
+	}
+
+	rec := get(t, r, "HEAD", "/blog/hello")
+	if rec.Code != http.StatusOK || rec.Body.Len() != 0 {
+		t.Errorf("HEAD: status = %d, body length = %d", rec.Code, rec.Body.Len())
+	}
+}
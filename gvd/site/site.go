@@ -0,0 +1,352 @@
+// site.go
+package site
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/TimLai666/go-vdom/dom"
+	"github.com/TimLai666/go-vdom/feed"
+	"github.com/TimLai666/go-vdom/gvd"
+	"github.com/TimLai666/go-vdom/runtime"
+)
+
+// ManifestFile 是記錄每個頁面渲染雜湊的檔案名稱，用於增量建置
+const ManifestFile = ".gvd-build.json"
+
+// Route 是一個靜態頁面：路徑加上產生頁面的函數
+type Route struct {
+	Path string
+	Page func() dom.VNode
+}
+
+// Registry 保存所有要輸出的路由
+type Registry struct {
+	routes map[string]Route
+}
+
+// NewRegistry 創建一個空的 Registry
+func NewRegistry() *Registry {
+	return &Registry{routes: make(map[string]Route)}
+}
+
+// DefaultRegistry 是 Register 與 Main 使用的預設 Registry
+var DefaultRegistry = NewRegistry()
+
+// Register 在 DefaultRegistry 中註冊一個頁面
+func Register(urlPath string, page func() dom.VNode) {
+	DefaultRegistry.Add(urlPath, page)
+}
+
+// Add 註冊一個頁面；相同路徑重複註冊時後者覆蓋前者
+// 路徑必須以 "/" 開頭，例如 "/"、"/about"、"/blog/hello"、"/404.html"
+func (r *Registry) Add(urlPath string, page func() dom.VNode) {
+	if !strings.HasPrefix(urlPath, "/") {
+		panic(fmt.Sprintf("site: route path %q must start with /", urlPath))
+	}
+	if strings.ContainsAny(urlPath, "{}") {
+		panic(fmt.Sprintf("site: route path %q must not contain wildcards", urlPath))
+	}
+	r.routes[urlPath] = Route{Path: urlPath, Page: page}
+}
+
+// Routes 返回依路徑排序的路由列表
+func (r *Registry) Routes() []Route {
+	routes := make([]Route, 0, len(r.routes))
+	for _, route := range r.routes {
+		routes = append(routes, route)
+	}
+	sort.Slice(routes, func(i, j int) bool { return routes[i].Path < routes[j].Path })
+	return routes
+}
+
+// Options 是建置選項
+type Options struct {
+	OutDir    string // 輸出目錄，預設 "dist"
+	StaticDir string // 靜態資源目錄，內容會原樣複製到 OutDir；空字串表示不複製
+	BaseURL   string // 站點根網址（如 "https://example.com"）；設定後會輸出 sitemap.xml
+	Force     bool   // 忽略增量建置紀錄，重寫所有頁面
+}
+
+// Result 記錄一次建置的結果
+type Result struct {
+	Written     []string // 重新寫入的頁面路徑
+	Skipped     []string // 內容未變而跳過的頁面路徑
+	Removed     []string // 已不在 Registry 中而從 OutDir 刪除的頁面路徑
+	RuntimeFile string   // runtime 腳本相對於 OutDir 的路徑
+}
+
+// manifest 是 ManifestFile 的內容
+type manifest struct {
+	Pages   map[string]string `json:"pages"`
+	Runtime string            `json:"runtime,omitempty"` // runtime 檔案相對於 OutDir 的路徑（以 / 分隔）
+}
+
+// Build 將 Registry 中的所有頁面渲染到 opts.OutDir
+//   - 客戶端 runtime 只寫出一次，檔名帶內容雜湊（如 assets/gvd-runtime.1a2b3c4d5e.js），
+//     頁面中由 Document 內聯的 runtime 會被替換成引用該檔案的 <script src>
+//   - 渲染結果的雜湊與上次建置相同且檔案仍存在時，跳過寫入
+//   - 上次建置紀錄中有、但已不在 Registry 中的頁面，以及內容已變更的舊 runtime 檔案會從 OutDir 刪除
+//   - 設定 BaseURL 時輸出 sitemap.xml（路徑以 .html 結尾的頁面如 /404.html 不列入）
+func Build(reg *Registry, opts Options) (Result, error) {
+	if opts.OutDir == "" {
+		opts.OutDir = "dist"
+	}
+	var res Result
+
+	if err := os.MkdirAll(opts.OutDir, 0o755); err != nil {
+		return res, fmt.Errorf("site: create output dir: %w", err)
+	}
+
+	runtimeFile, err := writeRuntime(opts.OutDir)
+	if err != nil {
+		return res, err
+	}
+	res.RuntimeFile = runtimeFile
+
+	if opts.StaticDir != "" {
+		if err := copyStatic(opts.StaticDir, opts.OutDir); err != nil {
+			return res, err
+		}
+	}
+
+	prev := readManifest(opts.OutDir)
+	next := manifest{Pages: make(map[string]string), Runtime: filepath.ToSlash(runtimeFile)}
+	runtimeSrc := "/" + next.Runtime
+
+	var sitemapPaths []string
+	outputs := make(map[string]bool)
+	for _, route := range reg.Routes() {
+		page := replaceRuntime(route.Page(), runtimeSrc)
+		html := gvd.RenderPage(page)
+		sum := sha256.Sum256([]byte(html))
+		hash := hex.EncodeToString(sum[:])
+		next.Pages[route.Path] = hash
+
+		out := filepath.Join(opts.OutDir, filepath.FromSlash(outputPath(route.Path)))
+		outputs[out] = true
+		if !opts.Force && prev.Pages[route.Path] == hash && fileExists(out) {
+			res.Skipped = append(res.Skipped, route.Path)
+		} else {
+			if err := writeFile(out, []byte(html)); err != nil {
+				return res, fmt.Errorf("site: write %s: %w", route.Path, err)
+			}
+			res.Written = append(res.Written, route.Path)
+		}
+
+		if !strings.HasSuffix(route.Path, ".html") {
+			sitemapPaths = append(sitemapPaths, route.Path)
+		}
+	}
+
+	removed, err := prunePages(opts.OutDir, prev, next, outputs)
+	res.Removed = removed
+	if err != nil {
+		return res, err
+	}
+
+	if opts.BaseURL != "" {
+		xml, err := feed.SitemapFromPaths(opts.BaseURL, sitemapPaths).XML()
+		if err != nil {
+			return res, fmt.Errorf("site: build sitemap: %w", err)
+		}
+		if err := writeFile(filepath.Join(opts.OutDir, "sitemap.xml"), []byte(xml)); err != nil {
+			return res, fmt.Errorf("site: write sitemap: %w", err)
+		}
+	}
+
+	if err := writeManifest(opts.OutDir, next); err != nil {
+		return res, err
+	}
+	return res, nil
+}
+
+// Main 解析命令列參數並建置 DefaultRegistry，供網站程式的 main 函數使用
+// 由 `gvd build` 以 `go run <pkg> -out ... -static ...` 呼叫
+//
+// 用法：
+//
+//	func main() {
+//	    site.Register("/", HomePage)
+//	    site.Register("/about", AboutPage)
+//	    site.Main()
+//	}
+func Main() {
+	flags := flag.NewFlagSet("site", flag.ExitOnError)
+	var opts Options
+	flags.StringVar(&opts.OutDir, "out", "dist", "output directory")
+	flags.StringVar(&opts.StaticDir, "static", "", "static asset directory copied into the output")
+	flags.StringVar(&opts.BaseURL, "base-url", "", "site base URL used for sitemap.xml")
+	flags.BoolVar(&opts.Force, "force", false, "rewrite every page even if unchanged")
+	_ = flags.Parse(os.Args[1:])
+
+	res, err := Build(DefaultRegistry, opts)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	fmt.Printf("gvd: %d pages written, %d unchanged, %d removed -> %s\n", len(res.Written), len(res.Skipped), len(res.Removed), opts.OutDir)
+}
+
+// outputPath 將路由路徑轉為輸出檔案路徑
+// "/" -> "index.html"、"/about" -> "about/index.html"、"/404.html" -> "404.html"
+func outputPath(urlPath string) string {
+	p := strings.Trim(path.Clean(urlPath), "/")
+	if p == "" {
+		return "index.html"
+	}
+	if path.Ext(p) == ".html" {
+		return p
+	}
+	return p + "/index.html"
+}
+
+// prunePages 刪除上次建置輸出、但本次已不在 Registry 中的頁面，並移除因此變空的目錄
+// 與現有頁面輸出到同一檔案的舊路徑（如 "/about/" 與 "/about"）不會被刪除
+// 上次建置的 runtime 檔案與本次不同時一併刪除；返回的列表只包含頁面路徑
+func prunePages(outDir string, prev, next manifest, outputs map[string]bool) ([]string, error) {
+	var stale []string
+	for p := range prev.Pages {
+		if _, ok := next.Pages[p]; !ok {
+			stale = append(stale, p)
+		}
+	}
+	sort.Strings(stale)
+
+	var removed []string
+	for _, p := range stale {
+		out := filepath.Join(outDir, filepath.FromSlash(outputPath(p)))
+		if outputs[out] {
+			continue
+		}
+		if err := os.Remove(out); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return removed, fmt.Errorf("site: remove %s: %w", p, err)
+		}
+		removed = append(removed, p)
+		// 由內而外移除空目錄，非空目錄的 Remove 會失敗並停止
+		for dir := filepath.Dir(out); dir != filepath.Clean(outDir); dir = filepath.Dir(dir) {
+			if os.Remove(dir) != nil {
+				break
+			}
+		}
+	}
+
+	// 只刪除符合 runtime 檔名格式的路徑，避免損壞的紀錄刪除其他檔案
+	if prev.Runtime != "" && prev.Runtime != next.Runtime {
+		if ok, _ := path.Match(runtimePattern, prev.Runtime); ok {
+			out := filepath.Join(outDir, filepath.FromSlash(prev.Runtime))
+			if err := os.Remove(out); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return removed, fmt.Errorf("site: remove runtime %s: %w", prev.Runtime, err)
+			}
+		}
+	}
+	return removed, nil
+}
+
+// runtimePattern 是 runtime 檔案相對於 OutDir 的路徑格式
+const runtimePattern = "assets/gvd-runtime.*.js"
+
+// writeRuntime 將客戶端 runtime 寫成帶雜湊的檔案，返回相對於 outDir 的路徑
+func writeRuntime(outDir string) (string, error) {
+	code := runtime.ClientRuntime()
+	sum := sha256.Sum256([]byte(code))
+	rel := filepath.Join("assets", "gvd-runtime."+hex.EncodeToString(sum[:5])+".js")
+	full := filepath.Join(outDir, rel)
+	if fileExists(full) {
+		return rel, nil
+	}
+	if err := writeFile(full, []byte(code)); err != nil {
+		return "", fmt.Errorf("site: write runtime: %w", err)
+	}
+	return rel, nil
+}
+
+// replaceRuntime 將 Document 內聯的 runtime <script> 替換為引用外部檔案的 <script src>
+func replaceRuntime(v dom.VNode, src string) dom.VNode {
+	if v.Tag == "script" && v.Content == runtime.ClientRuntime() {
+		return dom.Script(dom.Props{"src": src})
+	}
+	if len(v.Children) == 0 {
+		return v
+	}
+	children := make([]dom.VNode, len(v.Children))
+	for i, c := range v.Children {
+		children[i] = replaceRuntime(c, src)
+	}
+	v.Children = children
+	return v
+}
+
+// copyStatic 將 src 目錄下的檔案複製到 dst；內容相同的檔案不重寫
+func copyStatic(src, dst string) error {
+	return filepath.WalkDir(src, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(src, p)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return fmt.Errorf("site: read static %s: %w", rel, err)
+		}
+		target := filepath.Join(dst, rel)
+		if existing, err := os.ReadFile(target); err == nil && string(existing) == string(data) {
+			return nil
+		}
+		if err := writeFile(target, data); err != nil {
+			return fmt.Errorf("site: copy static %s: %w", rel, err)
+		}
+		return nil
+	})
+}
+
+func readManifest(outDir string) manifest {
+	m := manifest{Pages: make(map[string]string)}
+	data, err := os.ReadFile(filepath.Join(outDir, ManifestFile))
+	if err != nil {
+		return m
+	}
+	if err := json.Unmarshal(data, &m); err != nil || m.Pages == nil {
+		return manifest{Pages: make(map[string]string)}
+	}
+	return m
+}
+
+func writeManifest(outDir string, m manifest) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("site: encode manifest: %w", err)
+	}
+	if err := writeFile(filepath.Join(outDir, ManifestFile), data); err != nil {
+		return fmt.Errorf("site: write manifest: %w", err)
+	}
+	return nil
+}
+
+func writeFile(p string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(p, data, 0o644)
+}
+
+// fileExists 只有在 Stat 成功時返回 true；權限不足等錯誤視為不存在，頁面會被重寫
+func fileExists(p string) bool {
+	_, err := os.Stat(p)
+	return err == nil
+}
@@ -0,0 +1,210 @@
+package site
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/TimLai666/go-vdom/dom"
+	"github.com/TimLai666/go-vdom/runtime"
+)
+
+func testRegistry(title *string) *Registry {
+	reg := NewRegistry()
+	reg.Add("/", func() dom.VNode {
+		return dom.Document(*title, nil, nil, nil, dom.H1(nil, *title))
+	})
+	reg.Add("/blog/hello", func() dom.VNode {
+		return dom.Document("Hello", nil, nil, nil, dom.P(nil, "hello"))
+	})
+	reg.Add("/404.html", func() dom.VNode {
+		return dom.Document("Not Found", nil, nil, nil, dom.P(nil, "404"))
+	})
+	return reg
+}
+
+func TestBuildWritesPages(t *testing.T) {
+	out := t.TempDir()
+	static := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(static, "css"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(static, "css", "site.css"), []byte("body{}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	title := "Home"
+	res, err := Build(testRegistry(&title), Options{OutDir: out, StaticDir: static, BaseURL: "https://example.com"})
+	if err != nil {
+		t.Fatalf("Build error: %v", err)
+	}
+	if len(res.Written) != 3 {
+		t.Errorf("written = %v, want 3 pages", res.Written)
+	}
+
+	for _, p := range []string{"index.html", "blog/hello/index.html", "404.html", "css/site.css", "sitemap.xml", res.RuntimeFile} {
+		if _, err := os.Stat(filepath.Join(out, p)); err != nil {
+			t.Errorf("expected %s to exist: %v", p, err)
+		}
+	}
+
+	index, _ := os.ReadFile(filepath.Join(out, "index.html"))
+	html := string(index)
+	if !strings.HasPrefix(html, "<!DOCTYPE html>") {
+		t.Error("page should start with doctype")
+	}
+	if strings.Contains(html, runtime.ClientRuntime()) {
+		t.Error("runtime should not be inlined into pages")
+	}
+	if !strings.Contains(html, `<script src="/`+filepath.ToSlash(res.RuntimeFile)+`"></script>`) {
+		t.Errorf("page should reference the hashed runtime file %s", res.RuntimeFile)
+	}
+
+	sitemap, _ := os.ReadFile(filepath.Join(out, "sitemap.xml"))
+	if !strings.Contains(string(sitemap), "<loc>https://example.com/blog/hello</loc>") {
+		t.Errorf("sitemap missing page: %s", sitemap)
+	}
+	if strings.Contains(string(sitemap), "404") {
+		t.Error("sitemap should not list .html error pages")
+	}
+}
+
+func TestBuildIncremental(t *testing.T) {
+	out := t.TempDir()
+	title := "Home"
+	reg := testRegistry(&title)
+
+	if _, err := Build(reg, Options{OutDir: out}); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := Build(reg, Options{OutDir: out})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Written) != 0 || len(res.Skipped) != 3 {
+		t.Errorf("unchanged rebuild: written=%v skipped=%v", res.Written, res.Skipped)
+	}
+
+	title = "Changed"
+	res, err = Build(reg, Options{OutDir: out})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Written) != 1 || res.Written[0] != "/" {
+		t.Errorf("only / should be rewritten, got %v", res.Written)
+	}
+
+	if err := os.Remove(filepath.Join(out, "404.html")); err != nil {
+		t.Fatal(err)
+	}
+	res, err = Build(reg, Options{OutDir: out})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Written) != 1 || res.Written[0] != "/404.html" {
+		t.Errorf("deleted output should be rewritten, got %v", res.Written)
+	}
+
+	res, err = Build(reg, Options{OutDir: out, Force: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Written) != 3 {
+		t.Errorf("force should rewrite every page, got %v", res.Written)
+	}
+}
+
+func TestBuildPrunesRemovedPages(t *testing.T) {
+	out := t.TempDir()
+	title := "Home"
+	reg := testRegistry(&title)
+	reg.Add("/blog/old", func() dom.VNode {
+		return dom.Document("Old", nil, nil, nil, dom.P(nil, "old"))
+	})
+	if _, err := Build(reg, Options{OutDir: out}); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := Build(testRegistry(&title), Options{OutDir: out})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Removed) != 1 || res.Removed[0] != "/blog/old" {
+		t.Errorf("removed = %v, want [/blog/old]", res.Removed)
+	}
+	if _, err := os.Stat(filepath.Join(out, "blog", "old")); !os.IsNotExist(err) {
+		t.Errorf("stale page directory should be removed, stat err = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(out, "blog", "hello", "index.html")); err != nil {
+		t.Errorf("remaining page should be kept: %v", err)
+	}
+	manifest := readManifest(out)
+	if _, ok := manifest.Pages["/blog/old"]; ok {
+		t.Errorf("manifest should drop removed page: %v", manifest.Pages)
+	}
+
+	res, err = Build(testRegistry(&title), Options{OutDir: out})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Removed) != 0 {
+		t.Errorf("second build should remove nothing, got %v", res.Removed)
+	}
+}
+
+func TestBuildRemovesStaleRuntime(t *testing.T) {
+	out := t.TempDir()
+	title := "Home"
+	old := filepath.Join(out, "assets", "gvd-runtime.0123456789.js")
+	other := filepath.Join(out, "assets", "app.js")
+	for _, p := range []string{old, other} {
+		if err := writeFile(p, []byte("old")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writeManifest(out, manifest{Pages: map[string]string{}, Runtime: "assets/gvd-runtime.0123456789.js"}); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := Build(testRegistry(&title), Options{OutDir: out})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(old); !os.IsNotExist(err) {
+		t.Errorf("previous runtime should be removed, stat err = %v", err)
+	}
+	if _, err := os.Stat(other); err != nil {
+		t.Errorf("other assets should be kept: %v", err)
+	}
+	if got := readManifest(out).Runtime; got != filepath.ToSlash(res.RuntimeFile) {
+		t.Errorf("manifest runtime = %q, want %q", got, res.RuntimeFile)
+	}
+
+	// 紀錄中不符合 runtime 檔名格式的路徑不會被刪除
+	if err := writeManifest(out, manifest{Pages: readManifest(out).Pages, Runtime: "assets/app.js"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Build(testRegistry(&title), Options{OutDir: out}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(other); err != nil {
+		t.Errorf("non-runtime path in manifest must not be removed: %v", err)
+	}
+}
+
+func TestOutputPath(t *testing.T) {
+	tests := map[string]string{
+		"/":           "index.html",
+		"/about":      "about/index.html",
+		"/about/":     "about/index.html",
+		"/blog/hello": "blog/hello/index.html",
+		"/404.html":   "404.html",
+	}
+	for in, want := range tests {
+		if got := outputPath(in); got != want {
+			t.Errorf("outputPath(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
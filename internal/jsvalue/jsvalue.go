@@ -0,0 +1,325 @@
+// jsvalue.go
+package jsvalue
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// maxSafeInteger 是 JavaScript 能精確表示的最大整數（Number.MAX_SAFE_INTEGER）
+const maxSafeInteger = 1<<53 - 1
+
+// maxDepth 限制巢狀深度，避免自我引用的 map 或 slice 造成無窮遞迴
+const maxDepth = 1000
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// Encode 將 Go 值轉為 JavaScript 字面量，可直接放在 <script> 中或（經 HTML 屬性轉義後）內聯事件處理器中
+//   - 字串以雙引號輸出，並將 < > & ' U+2028 U+2029 與控制字元轉為 \uXXXX，不會出現 </script> 或 <!--
+//   - time.Time 輸出為 new Date("RFC 3339")
+//   - []byte 輸出為 new Uint8Array([...])
+//   - NaN 與正負無限大輸出為 NaN、Infinity、-Infinity
+//   - 超出 Number.MAX_SAFE_INTEGER 的整數輸出為字串，避免失去精度
+//   - map 的鍵依字典序輸出；非字串的鍵轉為字串（encoding.TextMarshaler 使用其文字形式）
+//   - json.Marshaler 與 struct 依 encoding/json 的規則序列化後轉為字面量；無法序列化的值輸出為 null
+//
+// 不含上述特殊型別的值，輸出同時是合法的 JSON
+func Encode(v any) string {
+	var sb strings.Builder
+	e := encoder{sb: &sb}
+	e.encode(reflect.ValueOf(v), 0)
+	return sb.String()
+}
+
+// TagKey 是 EncodeJSON 標記特殊值的物件鍵
+const TagKey = "$gvd"
+
+// EncodeJSON 與 Encode 相同，但輸出一律是合法的 JSON，可放在 HTML 屬性中並以 JSON.parse 解析
+// Encode 輸出為 JavaScript 運算式的值改以標記物件表示，由客戶端 runtime 解析時還原：
+//   - time.Time 輸出為 {"$gvd":"date","v":"RFC 3339"}
+//   - []byte 輸出為 {"$gvd":"bytes","v":[...]}
+//   - NaN 與正負無限大輸出為 {"$gvd":"number","v":"NaN"} 等
+func EncodeJSON(v any) string {
+	var sb strings.Builder
+	e := encoder{sb: &sb, strict: true}
+	e.encode(reflect.ValueOf(v), 0)
+	return sb.String()
+}
+
+// encoder 輸出 JavaScript 字面量；strict 時只輸出 JSON
+type encoder struct {
+	sb     *strings.Builder
+	strict bool
+}
+
+// tagged 輸出 strict 模式的標記物件 {"$gvd":kind,"v":...}，由 value 寫出 v 的值
+func (e encoder) tagged(kind string, value func()) {
+	e.sb.WriteString(`{"` + TagKey + `":"` + kind + `","v":`)
+	value()
+	e.sb.WriteString("}")
+}
+
+func (e encoder) encode(v reflect.Value, depth int) {
+	sb := e.sb
+	if !v.IsValid() || depth > maxDepth {
+		sb.WriteString("null")
+		return
+	}
+
+	t := v.Type()
+	switch {
+	case t == timeType:
+		date := v.Interface().(time.Time).Format(time.RFC3339Nano)
+		if e.strict {
+			e.tagged("date", func() { Quote(sb, date) })
+			return
+		}
+		sb.WriteString("new Date(")
+		Quote(sb, date)
+		sb.WriteString(")")
+		return
+	case t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 && !t.Implements(jsonMarshalerType):
+		if v.IsNil() {
+			sb.WriteString("null")
+			return
+		}
+		writeBytes := func() {
+			sb.WriteString("[")
+			for i, b := range v.Bytes() {
+				if i > 0 {
+					sb.WriteString(",")
+				}
+				sb.WriteString(strconv.Itoa(int(b)))
+			}
+			sb.WriteString("]")
+		}
+		if e.strict {
+			e.tagged("bytes", writeBytes)
+			return
+		}
+		sb.WriteString("new Uint8Array(")
+		writeBytes()
+		sb.WriteString(")")
+		return
+	case t.Kind() != reflect.Pointer && t.Implements(jsonMarshalerType),
+		t.Kind() == reflect.Pointer && !v.IsNil() && t.Implements(jsonMarshalerType) && t.Elem() != timeType:
+		e.encodeJSON(v.Interface(), depth)
+		return
+	case t.Kind() != reflect.Pointer && t.Implements(textMarshalerType):
+		text, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			sb.WriteString("null")
+			return
+		}
+		Quote(sb, string(text))
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			sb.WriteString("null")
+			return
+		}
+		e.encode(v.Elem(), depth+1)
+	case reflect.Bool:
+		sb.WriteString(strconv.FormatBool(v.Bool()))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n := v.Int()
+		if n > maxSafeInteger || n < -maxSafeInteger {
+			Quote(sb, strconv.FormatInt(n, 10))
+			return
+		}
+		sb.WriteString(strconv.FormatInt(n, 10))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n := v.Uint()
+		if n > maxSafeInteger {
+			Quote(sb, strconv.FormatUint(n, 10))
+			return
+		}
+		sb.WriteString(strconv.FormatUint(n, 10))
+	case reflect.Float32, reflect.Float64:
+		e.writeFloat(v.Float(), v.Type().Bits())
+	case reflect.String:
+		if n, ok := v.Interface().(json.Number); ok {
+			writeNumber(sb, n)
+			return
+		}
+		Quote(sb, v.String())
+	case reflect.Slice:
+		if v.IsNil() {
+			sb.WriteString("null")
+			return
+		}
+		fallthrough
+	case reflect.Array:
+		sb.WriteString("[")
+		for i := 0; i < v.Len(); i++ {
+			if i > 0 {
+				sb.WriteString(",")
+			}
+			e.encode(v.Index(i), depth+1)
+		}
+		sb.WriteString("]")
+	case reflect.Map:
+		if v.IsNil() {
+			sb.WriteString("null")
+			return
+		}
+		keys := make([]string, 0, v.Len())
+		values := make(map[string]reflect.Value, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			k := mapKey(iter.Key())
+			keys = append(keys, k)
+			values[k] = iter.Value()
+		}
+		sort.Strings(keys)
+		sb.WriteString("{")
+		for i, k := range keys {
+			if i > 0 {
+				sb.WriteString(",")
+			}
+			Quote(sb, k)
+			sb.WriteString(":")
+			e.encode(values[k], depth+1)
+		}
+		sb.WriteString("}")
+	case reflect.Struct:
+		e.encodeJSON(v.Interface(), depth)
+	default:
+		// chan、func 等無法表示的值
+		sb.WriteString("null")
+	}
+}
+
+// encodeJSON 以 encoding/json 序列化（遵循 json 標籤與 MarshalJSON），再將結果轉為安全的字面量
+func (e encoder) encodeJSON(v any, depth int) {
+	sb := e.sb
+	data, err := json.Marshal(v)
+	if err != nil {
+		sb.WriteString("null")
+		return
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var generic any
+	if err := dec.Decode(&generic); err != nil {
+		sb.WriteString("null")
+		return
+	}
+	e.encode(reflect.ValueOf(generic), depth+1)
+}
+
+// mapKey 返回 map 鍵的字串形式
+func mapKey(k reflect.Value) string {
+	if k.Kind() == reflect.String {
+		return k.String()
+	}
+	if tm, ok := k.Interface().(encoding.TextMarshaler); ok {
+		if text, err := tm.MarshalText(); err == nil {
+			return string(text)
+		}
+	}
+	switch k.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(k.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(k.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(k.Float(), 'g', -1, k.Type().Bits())
+	}
+	return fmt.Sprint(k.Interface())
+}
+
+func (e encoder) writeFloat(f float64, bits int) {
+	var special string
+	switch {
+	case math.IsNaN(f):
+		special = "NaN"
+	case math.IsInf(f, 1):
+		special = "Infinity"
+	case math.IsInf(f, -1):
+		special = "-Infinity"
+	default:
+		e.sb.WriteString(strconv.FormatFloat(f, 'g', -1, bits))
+		return
+	}
+	if e.strict {
+		e.tagged("number", func() { Quote(e.sb, special) })
+		return
+	}
+	e.sb.WriteString(special)
+}
+
+// writeNumber 輸出 json.Number；整數超出安全範圍時輸出為字串
+func writeNumber(sb *strings.Builder, n json.Number) {
+	if i, err := n.Int64(); err == nil {
+		if i > maxSafeInteger || i < -maxSafeInteger {
+			Quote(sb, n.String())
+			return
+		}
+	} else if !strings.ContainsAny(n.String(), ".eE") {
+		// 超出 int64 的整數
+		Quote(sb, n.String())
+		return
+	}
+	sb.WriteString(n.String())
+}
+
+// Quote 以雙引號輸出字串字面量；結果同時是合法的 JSON 字串
+func Quote(sb *strings.Builder, s string) {
+	quote(sb, s, '"')
+}
+
+// QuoteSingle 以單引號輸出字串字面量，單引號以 \' 轉義；其他字元的轉義規則與 Quote 相同
+func QuoteSingle(sb *strings.Builder, s string) {
+	quote(sb, s, '\'')
+}
+
+// quote 輸出以 q 為引號的字串字面量
+// < > & 以 \uXXXX 輸出，字面量中不會出現 </script> 或 <!--；雙引號字面量中的單引號同樣以 \u0027 輸出
+func quote(sb *strings.Builder, s string, q byte) {
+	const hex = "0123456789abcdef"
+	sb.WriteByte(q)
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case r == utf8.RuneError && size == 1:
+			sb.WriteString(`\ufffd`)
+		case r == rune(q):
+			sb.WriteByte('\\')
+			sb.WriteByte(q)
+		case r == '\\':
+			sb.WriteString(`\\`)
+		case r == '\n':
+			sb.WriteString(`\n`)
+		case r == '\r':
+			sb.WriteString(`\r`)
+		case r == '\t':
+			sb.WriteString(`\t`)
+		case r < 0x20, r == 0x7f, r == '<', r == '>', r == '&', r == '\'' && q == '"', r == '\u2028', r == '\u2029':
+			sb.WriteString(`\u`)
+			for shift := 12; shift >= 0; shift -= 4 {
+				sb.WriteByte(hex[(r>>shift)&0xF])
+			}
+		default:
+			sb.WriteString(s[i : i+size])
+		}
+		i += size
+	}
+	sb.WriteByte(q)
+}
@@ -0,0 +1,106 @@
+// jsvalue_test.go
+package jsvalue
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+type point struct {
+	X int    `json:"x"`
+	Y int    `json:"y,omitempty"`
+	N string `json:"-"`
+}
+
+type level int
+
+func (l level) MarshalText() ([]byte, error) { return []byte([]string{"low", "high"}[l]), nil }
+
+type upper string
+
+func (u upper) MarshalJSON() ([]byte, error) {
+	return json.Marshal(strings.ToUpper(string(u)) + "</script>")
+}
+
+func TestEncode(t *testing.T) {
+	when := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		name string
+		in   any
+		want string
+	}{
+		{"nil", nil, "null"},
+		{"string", "hi", `"hi"`},
+		{"script close", "</script><!--", `"\u003c/script\u003e\u003c!--"`},
+		{"quotes", `it's "ok" & done`, `"it\u0027s \"ok\" \u0026 done"`},
+		{"line separators", "a\u2028b\u2029c\n", `"a\u2028b\u2029c\n"`},
+		{"control", "\x00\x7f", `"\u0000\u007f"`},
+		{"invalid utf8", "\xff", `"\ufffd"`},
+		{"bool", true, "true"},
+		{"int", -42, "-42"},
+		{"unsafe int", int64(1) << 60, `"1152921504606846976"`},
+		{"float", 1.5, "1.5"},
+		{"nan", math.NaN(), "NaN"},
+		{"inf", math.Inf(-1), "-Infinity"},
+		{"time", when, `new Date("2024-01-02T03:04:05Z")`},
+		{"time pointer", &when, `new Date("2024-01-02T03:04:05Z")`},
+		{"bytes", []byte{1, 2, 255}, "new Uint8Array([1,2,255])"},
+		{"raw message", json.RawMessage(`{"a":"<b>"}`), `{"a":"\u003cb\u003e"}`},
+		{"nil slice", []int(nil), "null"},
+		{"slice", []any{1, "a", nil}, `[1,"a",null]`},
+		{"int keys", map[int]string{10: "b", 2: "a"}, `{"10":"b","2":"a"}`},
+		{"text keys", map[level]bool{1: true, 0: false}, `{"high":true,"low":false}`},
+		{"nested", map[string]any{"t": when, "b": []byte{7}}, `{"b":new Uint8Array([7]),"t":new Date("2024-01-02T03:04:05Z")}`},
+		{"struct", point{X: 1, N: "skip"}, `{"x":1}`},
+		{"marshaler", upper("a"), `"A\u003c/script\u003e"`},
+		{"text marshaler", level(1), `"high"`},
+		{"json number", json.Number("12345678901234567890"), `"12345678901234567890"`},
+		{"func", func() {}, "null"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Encode(tt.in); got != tt.want {
+				t.Errorf("Encode(%v) = %s, want %s", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEncodeIsJSONForPlainData(t *testing.T) {
+	in := map[string]any{"name": "<a href='x'>", "tags": []string{"a", "b"}, "n": 3.25, "ok": false}
+	var back map[string]any
+	if err := json.Unmarshal([]byte(Encode(in)), &back); err != nil {
+		t.Fatalf("output is not JSON: %v", err)
+	}
+	if back["name"] != in["name"] || back["n"] != 3.25 {
+		t.Errorf("round trip = %v", back)
+	}
+}
+
+func TestEncodeCycle(t *testing.T) {
+	m := map[string]any{}
+	m["self"] = m
+	if got := Encode(m); !strings.Contains(got, "null") {
+		t.Errorf("self-referential map should be cut off: %.80s", got)
+	}
+}
+
+func TestEncodeJSON(t *testing.T) {
+	when := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	in := map[string]any{"t": when, "b": []byte{7}, "n": math.Inf(1), "s": "</script>'", "x": []any{math.NaN(), 1}}
+	got := EncodeJSON(in)
+	want := `{"b":{"$gvd":"bytes","v":[7]},"n":{"$gvd":"number","v":"Infinity"},"s":"\u003c/script\u003e\u0027",` +
+		`"t":{"$gvd":"date","v":"2024-01-02T03:04:05Z"},"x":[{"$gvd":"number","v":"NaN"},1]}`
+	if got != want {
+		t.Errorf("EncodeJSON = %s, want %s", got, want)
+	}
+	if !json.Valid([]byte(got)) {
+		t.Errorf("EncodeJSON output is not JSON: %s", got)
+	}
+	if plain := map[string]any{"a": []int{1}, "s": "x"}; EncodeJSON(plain) != Encode(plain) {
+		t.Errorf("plain data should encode the same as Encode")
+	}
+}
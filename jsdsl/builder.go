@@ -9,12 +9,14 @@ import (
 // JSActionBuilder 用於收集和處理 JSAction
 type JSActionBuilder struct {
 	actions []JSAction
+	scope   *Scope
 }
 
 // NewJSActionBuilder 創建一個新的 JSActionBuilder
 func NewJSActionBuilder() *JSActionBuilder {
 	return &JSActionBuilder{
 		actions: []JSAction{},
+		scope:   NewScope(),
 	}
 }
 
@@ -31,8 +33,11 @@ func (b *JSActionBuilder) AddMany(actions ...JSAction) *JSActionBuilder {
 }
 
 // CreateElement 創建一個 DOM 元素並添加到 builder
-// 返回創建的元素，可以繼續操作
+// 返回創建的元素，可以繼續操作；未指定名稱時由 builder 的 Scope 分配
 func (b *JSActionBuilder) CreateElement(tagName string, varName ...string) Elem {
+	if len(varName) == 0 {
+		varName = []string{b.Scope().Fresh("el_" + tagName)}
+	}
 	elem, action := CreateEl(tagName, varName...)
 	b.Add(action)
 	return elem
@@ -68,6 +73,14 @@ func (b *JSActionBuilder) AppendChild(parent Elem, child Elem) *JSActionBuilder
 	return b
 }
 
+// Scope 返回 builder 的名稱分配器，在同一個 builder 中分配的名稱不會重複
+func (b *JSActionBuilder) Scope() *Scope {
+	if b.scope == nil {
+		b.scope = NewScope()
+	}
+	return b.scope
+}
+
 // GetActions 獲取所有收集的 JSAction
 func (b *JSActionBuilder) GetActions() []JSAction {
 	return b.actions
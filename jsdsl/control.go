@@ -0,0 +1,161 @@
+// control.go
+package jsdsl
+
+import (
+	"strings"
+
+	. "github.com/TimLai666/go-vdom/dom"
+)
+
+// block 將多個動作合併為以分號分隔的語句序列（忽略空動作），供 {} 區塊使用
+func block(actions []JSAction) string {
+	parts := make([]string, 0, len(actions))
+	for _, a := range actions {
+		line := strings.TrimSuffix(strings.TrimSpace(a.Code), ";")
+		if line != "" {
+			parts = append(parts, line)
+		}
+	}
+	return strings.Join(parts, ";")
+}
+
+// ifBuilder 用於構建 if / else if / else 語句的流暢 API
+type ifBuilder struct {
+	conds    []string
+	branches [][]JSAction
+}
+
+// If 創建一個 if 語句的構建器；cond 為 JavaScript 代碼字串、JSAction 或 Expr
+//
+// 用法：
+//
+//	js.If("!response.ok",
+//	    js.Throw("new Error('HTTP ' + response.status)"),
+//	).End()
+//
+//	js.If(js.Ident("n").Gt(js.Num(0)),
+//	    js.Log("'正數'"),
+//	).ElseIf("n < 0",
+//	    js.Log("'負數'"),
+//	).Else(
+//	    js.Log("'零'"),
+//	)
+//
+// 生成：if(n > 0){console.log('正數')}else if(n < 0){console.log('負數')}else{console.log('零')}
+func If(cond any, actions ...JSAction) *ifBuilder {
+	return &ifBuilder{conds: []string{code(cond)}, branches: [][]JSAction{actions}}
+}
+
+// ElseIf 添加 else if 分支，可以繼續鏈式調用
+func (ib *ifBuilder) ElseIf(cond any, actions ...JSAction) *ifBuilder {
+	ib.conds = append(ib.conds, code(cond))
+	ib.branches = append(ib.branches, actions)
+	return ib
+}
+
+// Else 添加 else 分支，返回最終的 JSAction
+func (ib *ifBuilder) Else(actions ...JSAction) JSAction {
+	var sb strings.Builder
+	ib.write(&sb)
+	sb.WriteString("else{" + scopedBlock(actions) + "}")
+	return JSAction{Code: sb.String()}
+}
+
+// End 結束 if 語句構建（不需要 else 時調用）
+func (ib *ifBuilder) End() JSAction {
+	var sb strings.Builder
+	ib.write(&sb)
+	return JSAction{Code: sb.String()}
+}
+
+func (ib *ifBuilder) write(sb *strings.Builder) {
+	for i, cond := range ib.conds {
+		if i > 0 {
+			sb.WriteString("else ")
+		}
+		sb.WriteString("if(" + cond + "){" + scopedBlock(ib.branches[i]) + "}")
+	}
+}
+
+// switchBuilder 用於構建 switch 語句的流暢 API
+type switchBuilder struct {
+	expr  string
+	cases []string
+}
+
+// Switch 創建一個 switch 語句的構建器；expr 為 JavaScript 代碼字串、JSAction 或 Expr
+// 每個 Case 的區塊結尾自動加上 break，不會意外落入下一個分支
+//
+// 用法：
+//
+//	js.Switch("action.type").
+//	    Case(js.Str("add"), js.Call("add", "action.item")).
+//	    Case(js.Str("clear"), js.Call("clear")).
+//	    Default(js.Log("'未知動作'"))
+//
+// 生成：switch(action.type){case 'add':{add(action.item);break}case 'clear':{clear();break}default:{console.log('未知動作')}}
+func Switch(expr any) *switchBuilder {
+	return &switchBuilder{expr: code(expr)}
+}
+
+// Case 添加一個分支；value 為 JavaScript 代碼字串、JSAction 或 Expr（字串字面量請使用 js.Str）
+func (sw *switchBuilder) Case(value any, actions ...JSAction) *switchBuilder {
+	body := scopedBlock(actions)
+	if body != "" {
+		body += ";"
+	}
+	body += "break"
+	sw.cases = append(sw.cases, "case "+code(value)+":{"+body+"}")
+	return sw
+}
+
+// Default 添加 default 分支，返回最終的 JSAction
+func (sw *switchBuilder) Default(actions ...JSAction) JSAction {
+	sw.cases = append(sw.cases, "default:{"+scopedBlock(actions)+"}")
+	return sw.End()
+}
+
+// End 結束 switch 語句構建（不需要 default 時調用）
+func (sw *switchBuilder) End() JSAction {
+	return JSAction{Code: "switch(" + sw.expr + "){" + strings.Join(sw.cases, "") + "}"}
+}
+
+// While 生成 while 迴圈；cond 為 JavaScript 代碼字串、JSAction 或 Expr
+//
+// 用法：js.While("node.parentElement", js.Ident("node").Assign(js.Ident("node").Dot("parentElement")).Action())
+func While(cond any, actions ...JSAction) JSAction {
+	return JSAction{Code: "while(" + code(cond) + "){" + scopedBlock(actions) + "}"}
+}
+
+// ForOf 生成 for...of 迴圈，遍歷任意可迭代對象；itemVar 以 const 宣告
+//
+// 用法：js.ForOf("data.items", "item", js.Log("item.name"))
+// 生成：for(const item of data.items){console.log(item.name)}
+func ForOf(iterable any, itemVar string, actions ...JSAction) JSAction {
+	return JSAction{Code: "for(const " + itemVar + " of " + code(iterable) + "){" + scopedBlock(actions, itemVar) + "}"}
+}
+
+// Return 生成 return 語句；不傳值時為單獨的 return
+func Return(value ...any) JSAction {
+	if len(value) == 0 {
+		return JSAction{Code: "return"}
+	}
+	return JSAction{Code: "return " + code(value[0])}
+}
+
+// Throw 生成 throw 語句；value 為 JavaScript 代碼字串、JSAction 或 Expr
+//
+// 用法：js.Throw("new Error('HTTP ' + response.status)")
+func Throw(value any) JSAction {
+	return JSAction{Code: "throw " + code(value)}
+}
+
+// Break 生成 break 語句
+func Break() JSAction {
+	return JSAction{Code: "break"}
+}
+
+// Continue 生成 continue 語句
+func Continue() JSAction {
+	return JSAction{Code: "continue"}
+}
@@ -0,0 +1,63 @@
+// control_test.go
+package jsdsl
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/TimLai666/go-vdom/dom"
+)
+
+func TestControlFlow(t *testing.T) {
+	n := Ident("n")
+	tests := []struct {
+		name   string
+		action JSAction
+		want   string
+	}{
+		{"if", If("a", Log("1")).End(), "if(a){console.log(1)}"},
+		{
+			"if else-if else",
+			If(n.Gt(Num(0)), Log("'pos'")).ElseIf("n < 0", Log("'neg'")).Else(Log("'zero'")),
+			"if(n > 0){console.log('pos')}else if(n < 0){console.log('neg')}else{console.log('zero')}",
+		},
+		{
+			"nested if",
+			If("a", If("b", Return()).Else(Break())).ElseIf("c").Else(),
+			"if(a){if(b){return}else{break}}else if(c){}else{}",
+		},
+		{
+			"switch breaks every case",
+			Switch("t").Case(Str("a"), Call("f")).Case(Str("b")).Default(Log("x")),
+			"switch(t){case 'a':{f();break}case 'b':{break}default:{console.log(x)}}",
+		},
+		{"switch without default", Switch(n).Case(Num(1), Continue()).End(), "switch(n){case 1:{continue;break}}"},
+		{
+			"while",
+			While("node.parentElement", Ident("node").Assign(Ident("node").Dot("parentElement")).Action()),
+			"while(node.parentElement){node = node.parentElement}",
+		},
+		{
+			"for of skips empty statements",
+			ForOf("data.items", "item", Log("item.name"), JSAction{Code: "  ;"}, JSAction{Code: "count++;"}),
+			"for(const item of data.items){console.log(item.name);count++}",
+		},
+		{"return value", Return(n.Add(Num(1))), "return n + 1"},
+		{"bare return", Return(), "return"},
+		{"throw", Throw(New(Ident("Error"), Str("x"))), "throw new Error('x')"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.action.Code != tt.want {
+				t.Errorf("got  %s\nwant %s", tt.action.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestSwitchCasesDoNotFallThrough(t *testing.T) {
+	code := Switch("x").Case(Str("a")).Case(Str("b")).Case(Str("c")).End().Code
+	if got, want := strings.Count(code, "break"), 3; got != want {
+		t.Errorf("%s: %d breaks, want %d", code, got, want)
+	}
+}
@@ -0,0 +1,186 @@
+// events.go
+package jsdsl
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	. "github.com/TimLai666/go-vdom/dom"
+)
+
+// Event 代表事件處理器中的事件物件
+// Target 為處理器所對應的元素：On 綁定的元素，或 Delegate 中與 selector 相符的元素
+type Event struct {
+	Target Elem
+}
+
+// eventVar 是處理器中事件參數的名稱
+const eventVar = "event"
+
+// Expr 返回事件物件的表達式
+func (e Event) Expr() Expr {
+	return Ident(eventVar)
+}
+
+// Get 返回事件屬性的表達式，例如 ev.Get("key") 輸出 event.key
+func (e Event) Get(prop string) Expr {
+	return e.Expr().Dot(prop)
+}
+
+// PreventDefault 輸出 event.preventDefault()
+func (e Event) PreventDefault() JSAction {
+	return e.Expr().Method("preventDefault").Action()
+}
+
+// StopPropagation 輸出 event.stopPropagation()
+func (e Event) StopPropagation() JSAction {
+	return e.Expr().Method("stopPropagation").Action()
+}
+
+// EventOptions 是 addEventListener 的選項
+// Signal 為 AbortSignal 的 JavaScript 代碼字串或 Expr（如 "controller.signal"），中止時自動移除監聽器
+type EventOptions struct {
+	Once    bool
+	Passive bool
+	Capture bool
+	Signal  any
+}
+
+// object 輸出選項物件；沒有任何選項時返回空字串
+func (o EventOptions) object() string {
+	var parts []string
+	if o.Capture {
+		parts = append(parts, "capture:true")
+	}
+	if o.Once {
+		parts = append(parts, "once:true")
+	}
+	if o.Passive {
+		parts = append(parts, "passive:true")
+	}
+	if o.Signal != nil {
+		parts = append(parts, "signal:"+code(o.Signal))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+func mergeEventOptions(opts []EventOptions) EventOptions {
+	var merged EventOptions
+	for _, o := range opts {
+		merged.Once = merged.Once || o.Once
+		merged.Passive = merged.Passive || o.Passive
+		merged.Capture = merged.Capture || o.Capture
+		if o.Signal != nil {
+			merged.Signal = o.Signal
+		}
+	}
+	return merged
+}
+
+// Handler 將 Go 函數轉為接收事件物件的 JavaScript 函數 function(event){...}
+// 可搭配 Const 命名，之後以相同名稱傳給 On 與 Off
+//
+// 用法：
+//
+//	js.Const("onKey", js.Handler(func(ev js.Event) JSAction {
+//	    return js.Log(ev.Get("key"))
+//	}))
+func Handler(fn func(ev Event) JSAction) JSAction {
+	ev := Event{Target: Elem{VarName: eventVar + ".currentTarget"}}
+	return JSAction{Code: "function(" + eventVar + "){" + resolveLocals(block([]JSAction{fn(ev)}), eventVar) + "}"}
+}
+
+// listenerCode 返回監聽器函數的代碼；handler 為 func(Event) JSAction，或已命名函數的代碼字串、JSAction、Expr
+func listenerCode(handler any) string {
+	if fn, ok := handler.(func(ev Event) JSAction); ok {
+		return Handler(fn).Code
+	}
+	return code(handler)
+}
+
+func addListener(target, event string, handler any, opts []EventOptions) string {
+	args := Str(event).String() + "," + listenerCode(handler)
+	if o := mergeEventOptions(opts).object(); o != "" {
+		args += "," + o
+	}
+	return fmt.Sprintf("%s.addEventListener(%s)", target, args)
+}
+
+// handlerRefPattern 匹配監聽器的名稱：識別字或以 . 連接的成員存取（如 onKey、this.handlers.key）
+var handlerRefPattern = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*(\.[A-Za-z_$][A-Za-z0-9_$]*)*$`)
+
+// handlerRef 返回具名監聽器的代碼；removeEventListener 只能以註冊時的同一函數移除，
+// 因此 func(Event) JSAction 與函數字面量等每次都會產生新函數的參數一律 panic
+func handlerRef(handler any) string {
+	var ref string
+	switch t := handler.(type) {
+	case string:
+		ref = strings.TrimSpace(t)
+	case Expr:
+		ref = t.String()
+	}
+	if !handlerRefPattern.MatchString(ref) {
+		panic(fmt.Sprintf("jsdsl: Off requires a named handler such as \"onKey\" or js.Ident(\"onKey\"), got %T %v", handler, handler))
+	}
+	return ref
+}
+
+func removeListener(target, event string, handler any, opts []EventOptions) string {
+	args := Str(event).String() + "," + handlerRef(handler)
+	if mergeEventOptions(opts).Capture {
+		// 移除時只有 capture 需要與註冊時一致
+		args += ",true"
+	}
+	return fmt.Sprintf("%s.removeEventListener(%s)", target, args)
+}
+
+// On 為元素綁定事件監聽器
+// handler 為 func(Event) JSAction，或已命名函數的代碼字串、JSAction、Expr（需要 Off 時使用）
+//
+// 用法：
+//
+//	js.El("#search").On("input", func(ev js.Event) JSAction {
+//	    return js.Call("search", ev.Target.Expr().Dot("value"))
+//	}, js.EventOptions{Passive: true})
+func (el Elem) On(event string, handler any, opts ...EventOptions) JSAction {
+	return JSAction{Code: addListener(el.Ref(), event, handler, opts)}
+}
+
+// Off 移除以 On 綁定的具名監聽器；Capture 選項需與綁定時相同
+// handler 必須是監聽器的名稱（識別字字串或 Expr，如 "onKey"、js.Ident("onKey")），否則 panic
+func (el Elem) Off(event string, handler any, opts ...EventOptions) JSAction {
+	return JSAction{Code: removeListener(el.Ref(), event, handler, opts)}
+}
+
+// On 為列表中的每個元素綁定事件監聽器
+func (e ElemList) On(event string, handler any, opts ...EventOptions) JSAction {
+	return JSAction{Code: fmt.Sprintf("%s.forEach(function(el){%s})", e.Ref(), addListener("el", event, handler, opts))}
+}
+
+// Off 移除列表中每個元素以 On 綁定的具名監聽器；handler 的規則與 Elem.Off 相同
+func (e ElemList) Off(event string, handler any, opts ...EventOptions) JSAction {
+	return JSAction{Code: fmt.Sprintf("%s.forEach(function(el){%s})", e.Ref(), removeListener("el", event, handler, opts))}
+}
+
+// Delegate 在 root 上以事件委派處理 selector 相符的子元素事件，適用於 ForEachJS 等動態渲染的列表
+// root 為 Elem、JavaScript 代碼字串或 Expr；ev.Target 為 event.target 最近的相符祖先元素（限 root 內）
+//
+// 用法：
+//
+//	js.Delegate(js.El("#todo-list"), "click", ".remove", func(ev js.Event) JSAction {
+//	    return ev.Target.Expr().Method("closest", js.Str("li")).Method("remove").Action()
+//	})
+func Delegate(root any, event, selector string, handler func(ev Event) JSAction, opts ...EventOptions) JSAction {
+	target := Elem{VarName: "target"}
+	body := block([]JSAction{
+		Const("target", Ident(eventVar).Dot("target").Method("closest", Str(selector))),
+		If("!target||!this.contains(target)", Return()).End(),
+		handler(Event{Target: target}),
+	})
+	listener := "function(" + eventVar + "){" + resolveLocals(body, eventVar) + "}"
+	return JSAction{Code: addListener(code(root), event, listener, opts)}
+}
@@ -0,0 +1,93 @@
+// events_test.go
+package jsdsl
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/TimLai666/go-vdom/dom"
+)
+
+func TestOnOff(t *testing.T) {
+	search := func(ev Event) JSAction { return Call("search", ev.Target.Expr().Dot("value")) }
+	tests := []struct {
+		name   string
+		action JSAction
+		want   string
+	}{
+		{
+			"func handler with merged options",
+			El("#q").On("input", search, EventOptions{Passive: true}, EventOptions{Once: true, Signal: "ctl.signal"}),
+			"document.querySelector('#q').addEventListener('input',function(event){search(event.currentTarget.value)},{once:true,passive:true,signal:ctl.signal})",
+		},
+		{
+			"named handler",
+			El("#q").On("keydown", "onKey", EventOptions{Capture: true}),
+			"document.querySelector('#q').addEventListener('keydown',onKey,{capture:true})",
+		},
+		{
+			"off keeps only capture",
+			El("#q").Off("keydown", "onKey", EventOptions{Capture: true, Passive: true}),
+			"document.querySelector('#q').removeEventListener('keydown',onKey,true)",
+		},
+		{
+			"list on",
+			Els(".b").On("click", Ident("onClick")),
+			"document.querySelectorAll('.b').forEach(function(el){el.addEventListener('click',onClick)})",
+		},
+		{
+			"list off with member handler",
+			Els(".b").Off("click", Ident("handlers").Dot("click")),
+			"document.querySelectorAll('.b').forEach(function(el){el.removeEventListener('click',handlers.click)})",
+		},
+		{
+			"handler",
+			Handler(func(ev Event) JSAction { return Log(ev.Get("key")) }),
+			"function(event){console.log(event.key)}",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.action.Code != tt.want {
+				t.Errorf("got  %s\nwant %s", tt.action.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestOffRejectsAnonymousHandlers(t *testing.T) {
+	for name, handler := range map[string]any{
+		"func":             func(ev Event) JSAction { return ev.PreventDefault() },
+		"function literal": "function(event){}",
+		"arrow function":   Raw("e=>e.preventDefault()"),
+		"action":           Handler(func(ev Event) JSAction { return ev.PreventDefault() }),
+		"empty":            "",
+	} {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if r := recover(); r == nil || !strings.Contains(r.(string), "named handler") {
+					t.Errorf("Off(%v) should panic, recovered %v", handler, r)
+				}
+			}()
+			El("#q").Off("click", handler)
+		})
+	}
+}
+
+func TestDelegate(t *testing.T) {
+	remove := Delegate(El("#list"), "click", ".remove", func(ev Event) JSAction {
+		return ev.Target.Expr().Method("remove").Action()
+	})
+	want := "document.querySelector('#list').addEventListener('click',function(event){const target=event.target.closest('.remove');if(!target||!this.contains(target)){return};target.remove()})"
+	if remove.Code != want {
+		t.Errorf("got  %s\nwant %s", remove.Code, want)
+	}
+
+	capture := Delegate("document.body", "input", "input[name='q']", func(ev Event) JSAction {
+		return ev.PreventDefault()
+	}, EventOptions{Capture: true})
+	want = `document.body.addEventListener('input',function(event){const target=event.target.closest('input[name=\'q\']');if(!target||!this.contains(target)){return};event.preventDefault()},{capture:true})`
+	if capture.Code != want {
+		t.Errorf("got  %s\nwant %s", capture.Code, want)
+	}
+}
@@ -0,0 +1,441 @@
+// expr.go
+package jsdsl
+
+import (
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	. "github.com/TimLai666/go-vdom/dom"
+	"github.com/TimLai666/go-vdom/internal/jsvalue"
+)
+
+// Expr 是 JavaScript 表達式的語法樹節點
+// 以 Str、Num、Ident 等建構，再以 Dot、Call、Eq 等方法組合；String() 輸出正確轉義的代碼，
+// 並只在運算子優先順序需要時加上括號
+//
+// 用法：
+//
+//	Ident("console").Method("log", Str("it's ok"), Ident("data").Dot("length"))
+//	// console.log('it\'s ok', data.length)
+//	Cond(Ident("n").Gt(Num(0)), Str("yes"), Str("no"))
+//	// n > 0 ? 'yes' : 'no'
+type Expr struct {
+	n node
+}
+
+// node 是語法樹中的一種表達式
+type node interface {
+	write(sb *strings.Builder)
+	prec() int
+}
+
+// 運算子優先順序，數值越大結合越緊
+const (
+	precRaw     = iota // 未知結構的原始代碼（可能含逗號），作為運算元時一律加括號
+	precAssign         // = 與箭頭函數
+	precCond           // ?:
+	precOr             // ||
+	precAnd            // &&
+	precEq             // === !==
+	precRel            // < <= > >= instanceof in
+	precAdd            // + -
+	precMul            // * / %
+	precUnary          // ! - typeof await
+	precNew            // new 與數字字面量（作為成員存取對象時需要括號）
+	precCall           // 成員存取與呼叫
+	precPrimary        // 字面量與識別字
+)
+
+// String 返回表達式的 JavaScript 代碼
+func (e Expr) String() string {
+	var sb strings.Builder
+	e.write(&sb)
+	return sb.String()
+}
+
+func (e Expr) write(sb *strings.Builder) {
+	if e.n == nil {
+		sb.WriteString("undefined")
+		return
+	}
+	e.n.write(sb)
+}
+
+func (e Expr) prec() int {
+	if e.n == nil {
+		return precPrimary
+	}
+	return e.n.prec()
+}
+
+// writeOperand 輸出運算元，優先順序低於 min 時加上括號
+func (e Expr) writeOperand(sb *strings.Builder, min int) {
+	if e.prec() < min {
+		sb.WriteString("(")
+		e.write(sb)
+		sb.WriteString(")")
+		return
+	}
+	e.write(sb)
+}
+
+// Action 將表達式作為語句使用
+func (e Expr) Action() JSAction {
+	code := e.String()
+	// 以 { 開頭的語句會被解析為區塊
+	if strings.HasPrefix(code, "{") {
+		code = "(" + code + ")"
+	}
+	return JSAction{Code: code}
+}
+
+// literal 是已輸出的字面量或識別字
+type literal struct {
+	code string
+	p    int
+}
+
+func (l literal) write(sb *strings.Builder) { sb.WriteString(l.code) }
+func (l literal) prec() int                 { return l.p }
+
+// Raw 將任意 JavaScript 代碼包裝為表達式；作為運算元或成員存取對象時會加上括號
+func Raw(code string) Expr {
+	return Expr{literal{code: code, p: precRaw}}
+}
+
+// Ident 返回識別字（變數名稱或全域物件），例如 Ident("window")
+func Ident(name string) Expr {
+	return Expr{literal{code: name, p: precPrimary}}
+}
+
+// This 返回 this
+func This() Expr { return Ident("this") }
+
+// Str 返回字串字面量，以單引號輸出並轉義引號、換行、U+2028/U+2029 與 < > &（不會出現 </script> 或 <!--）
+func Str(s string) Expr {
+	return Expr{literal{code: quote(s), p: precPrimary}}
+}
+
+// Num 返回數字字面量；NaN 與正負無限大輸出為 NaN、Infinity、-Infinity
+func Num(f float64) Expr {
+	var code string
+	switch {
+	case math.IsNaN(f):
+		code = "NaN"
+	case math.IsInf(f, 1):
+		code = "Infinity"
+	case math.IsInf(f, -1):
+		code = "-Infinity"
+	default:
+		code = strconv.FormatFloat(f, 'g', -1, 64)
+	}
+	return Expr{literal{code: code, p: precNew}}
+}
+
+// Bool 返回 true 或 false
+func Bool(b bool) Expr {
+	return Expr{literal{code: strconv.FormatBool(b), p: precPrimary}}
+}
+
+// Null 返回 null
+func Null() Expr { return Expr{literal{code: "null", p: precPrimary}} }
+
+// Undefined 返回 undefined
+func Undefined() Expr { return Expr{} }
+
+// Arr 返回陣列字面量
+func Arr(items ...Expr) Expr {
+	return Expr{arrayNode(items)}
+}
+
+type arrayNode []Expr
+
+func (a arrayNode) write(sb *strings.Builder) {
+	sb.WriteString("[")
+	writeList(sb, a)
+	sb.WriteString("]")
+}
+func (a arrayNode) prec() int { return precPrimary }
+
+// Obj 返回物件字面量，鍵依字典序輸出；不是合法識別字的鍵會加上引號
+func Obj(fields map[string]Expr) Expr {
+	return Expr{objectNode(fields)}
+}
+
+type objectNode map[string]Expr
+
+func (o objectNode) write(sb *strings.Builder) {
+	keys := make([]string, 0, len(o))
+	for k := range o {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	sb.WriteString("{")
+	for i, k := range keys {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		if identPattern.MatchString(k) {
+			sb.WriteString(k)
+		} else {
+			sb.WriteString(quote(k))
+		}
+		sb.WriteString(": ")
+		o[k].writeOperand(sb, precAssign)
+	}
+	sb.WriteString("}")
+}
+func (o objectNode) prec() int { return precPrimary }
+
+// member 是 obj.name 或 obj[index]
+type member struct {
+	obj   Expr
+	name  string
+	index *Expr
+}
+
+func (m member) write(sb *strings.Builder) {
+	m.obj.writeOperand(sb, precCall)
+	if m.index != nil {
+		sb.WriteString("[")
+		m.index.write(sb)
+		sb.WriteString("]")
+		return
+	}
+	sb.WriteString("." + m.name)
+}
+func (m member) prec() int { return precCall }
+
+// Dot 返回成員存取 e.name；name 不是合法識別字時輸出為 e['name']
+func (e Expr) Dot(name string) Expr {
+	if !identPattern.MatchString(name) {
+		return e.Index(Str(name))
+	}
+	return Expr{member{obj: e, name: name}}
+}
+
+// Index 返回 e[index]
+func (e Expr) Index(index Expr) Expr {
+	return Expr{member{obj: e, index: &index}}
+}
+
+// call 是函數呼叫或 new 運算式
+type call struct {
+	fn    Expr
+	args  []Expr
+	isNew bool
+}
+
+func (c call) write(sb *strings.Builder) {
+	if _, isCall := c.fn.n.(call); c.isNew && isCall {
+		// new f()() 與 new (f())() 意義不同
+		sb.WriteString("new (")
+		c.fn.write(sb)
+		sb.WriteString(")")
+	} else if c.isNew {
+		sb.WriteString("new ")
+		c.fn.writeOperand(sb, precCall)
+	} else {
+		c.fn.writeOperand(sb, precCall)
+	}
+	sb.WriteString("(")
+	writeList(sb, c.args)
+	sb.WriteString(")")
+}
+func (c call) prec() int { return precCall }
+
+// Call 返回函數呼叫 e(args...)
+func (e Expr) Call(args ...Expr) Expr {
+	return Expr{call{fn: e, args: args}}
+}
+
+// Method 返回方法呼叫 e.name(args...)
+func (e Expr) Method(name string, args ...Expr) Expr {
+	return e.Dot(name).Call(args...)
+}
+
+// New 返回 new ctor(args...)
+func New(ctor Expr, args ...Expr) Expr {
+	return Expr{call{fn: ctor, args: args, isNew: true}}
+}
+
+// binary 是二元運算式（包含賦值）
+type binary struct {
+	op   string
+	l, r Expr
+	p    int
+}
+
+func (b binary) write(sb *strings.Builder) {
+	if b.p == precAssign {
+		// 賦值為右結合
+		b.l.writeOperand(sb, precCall)
+		sb.WriteString(" " + b.op + " ")
+		b.r.writeOperand(sb, precAssign)
+		return
+	}
+	b.l.writeOperand(sb, b.p)
+	sb.WriteString(" " + b.op + " ")
+	b.r.writeOperand(sb, b.p+1)
+}
+func (b binary) prec() int { return b.p }
+
+func (e Expr) binary(op string, p int, other Expr) Expr {
+	return Expr{binary{op: op, l: e, r: other, p: p}}
+}
+
+// Assign 返回賦值運算式 e = value
+func (e Expr) Assign(value Expr) Expr { return e.binary("=", precAssign, value) }
+
+// Eq 返回 e === other
+func (e Expr) Eq(other Expr) Expr { return e.binary("===", precEq, other) }
+
+// NotEq 返回 e !== other
+func (e Expr) NotEq(other Expr) Expr { return e.binary("!==", precEq, other) }
+
+// Lt 返回 e < other
+func (e Expr) Lt(other Expr) Expr { return e.binary("<", precRel, other) }
+
+// Le 返回 e <= other
+func (e Expr) Le(other Expr) Expr { return e.binary("<=", precRel, other) }
+
+// Gt 返回 e > other
+func (e Expr) Gt(other Expr) Expr { return e.binary(">", precRel, other) }
+
+// Ge 返回 e >= other
+func (e Expr) Ge(other Expr) Expr { return e.binary(">=", precRel, other) }
+
+// Add 返回 e + other（數字相加或字串串接）
+func (e Expr) Add(other Expr) Expr { return e.binary("+", precAdd, other) }
+
+// Sub 返回 e - other
+func (e Expr) Sub(other Expr) Expr { return e.binary("-", precAdd, other) }
+
+// Mul 返回 e * other
+func (e Expr) Mul(other Expr) Expr { return e.binary("*", precMul, other) }
+
+// Div 返回 e / other
+func (e Expr) Div(other Expr) Expr { return e.binary("/", precMul, other) }
+
+// Mod 返回 e % other
+func (e Expr) Mod(other Expr) Expr { return e.binary("%", precMul, other) }
+
+// And 返回 e && other
+func (e Expr) And(other Expr) Expr { return e.binary("&&", precAnd, other) }
+
+// Or 返回 e || other
+func (e Expr) Or(other Expr) Expr { return e.binary("||", precOr, other) }
+
+// InstanceOf 返回 e instanceof ctor
+func (e Expr) InstanceOf(ctor Expr) Expr { return e.binary("instanceof", precRel, ctor) }
+
+// unary 是前置一元運算式
+type unary struct {
+	op string
+	x  Expr
+}
+
+func (u unary) write(sb *strings.Builder) {
+	sb.WriteString(u.op)
+	var operand strings.Builder
+	u.x.writeOperand(&operand, precUnary)
+	code := operand.String()
+	// 避免 - -1 被輸出為遞減運算子 --1
+	if last := u.op[len(u.op)-1]; (last == '-' || last == '+') && strings.HasPrefix(code, string(last)) {
+		sb.WriteString(" ")
+	}
+	sb.WriteString(code)
+}
+func (u unary) prec() int { return precUnary }
+
+// Not 返回 !x
+func Not(x Expr) Expr { return Expr{unary{op: "!", x: x}} }
+
+// Neg 返回 -x
+func Neg(x Expr) Expr { return Expr{unary{op: "-", x: x}} }
+
+// Typeof 返回 typeof x
+func Typeof(x Expr) Expr { return Expr{unary{op: "typeof ", x: x}} }
+
+// Await 返回 await x（只能在 AsyncFn / AsyncDo 中使用）
+func Await(x Expr) Expr { return Expr{unary{op: "await ", x: x}} }
+
+// conditional 是三元運算式
+type conditional struct {
+	test, then, els Expr
+}
+
+func (c conditional) write(sb *strings.Builder) {
+	c.test.writeOperand(sb, precOr)
+	sb.WriteString(" ? ")
+	c.then.writeOperand(sb, precAssign)
+	sb.WriteString(" : ")
+	c.els.writeOperand(sb, precAssign)
+}
+func (c conditional) prec() int { return precCond }
+
+// Cond 返回三元運算式 test ? then : els
+func Cond(test, then, els Expr) Expr {
+	return Expr{conditional{test: test, then: then, els: els}}
+}
+
+// writeList 輸出以逗號分隔的表達式（陣列元素或參數）
+func writeList(sb *strings.Builder, items []Expr) {
+	for i, item := range items {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		// 原始代碼在參數位置保持原樣，沿用 Log("'a:', b") 這類多參數字串的寫法
+		if item.prec() == precRaw {
+			item.write(sb)
+			continue
+		}
+		item.writeOperand(sb, precAssign)
+	}
+}
+
+var identPattern = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// quote 將字串轉為單引號 JavaScript 字串字面量，轉義規則與 Value 的字串相同
+func quote(s string) string {
+	var sb strings.Builder
+	jsvalue.QuoteSingle(&sb, s)
+	return sb.String()
+}
+
+// toExpr 將 helper 的參數轉為表達式：字串與 JSAction 視為 JavaScript 代碼（與既有 helper 相同），
+// 其他 Go 值（布林值、數字、map、slice、struct 等）以 Value 轉為字面量
+func toExpr(v any) Expr {
+	switch t := v.(type) {
+	case Expr:
+		return t
+	case string:
+		return Raw(t)
+	case JSAction:
+		return Raw(t.Code)
+	case Elem:
+		return t.Expr()
+	case ElemList:
+		return t.Expr()
+	case nil:
+		return Null()
+	default:
+		return Value(v)
+	}
+}
+
+// code 返回 helper 參數的代碼
+func code(v any) string {
+	return toExpr(v).String()
+}
+
+// literalOr 將字串參數視為字串字面量（輸出時加上引號），其他參數與 toExpr 相同
+func literalOr(v any) Expr {
+	if s, ok := v.(string); ok {
+		return Str(s)
+	}
+	return toExpr(v)
+}
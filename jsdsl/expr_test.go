@@ -0,0 +1,135 @@
+// expr_test.go
+package jsdsl
+
+import (
+	"testing"
+)
+
+func TestStrQuoting(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"single quote and backslash", `it's a \ test`, `'it\'s a \\ test'`},
+		{"double quote kept", `"double"`, `'"double"'`},
+		{"script and comment", "</script><!-- a&b >", `'\u003c/script\u003e\u003c!-- a\u0026b \u003e'`},
+		{"line terminators", "a\u2028b\u2029\n\r\t", `'a\u2028b\u2029\n\r\t'`},
+		{"control characters", "\x01\x7f", `'\u0001\u007f'`},
+		{"invalid utf-8", "a\xffb", `'a\ufffdb'`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Str(tt.in).String(); got != tt.want {
+				t.Errorf("Str(%q) = %s, want %s", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStrMatchesValueEscaping(t *testing.T) {
+	// Str 與 Value 對 < > & 與行分隔符使用相同的轉義
+	s := "<!--</script>&\u2028"
+	str, val := Str(s).String(), Value(s).String()
+	if str[1:len(str)-1] != val[1:len(val)-1] {
+		t.Errorf("Str = %s, Value = %s: escapes differ", str, val)
+	}
+}
+
+func TestExprPrecedence(t *testing.T) {
+	a, b, c := Ident("a"), Ident("b"), Ident("c")
+	tests := []struct {
+		name string
+		expr Expr
+		want string
+	}{
+		{"add inside mul", a.Add(b).Mul(c), "(a + b) * c"},
+		{"mul inside add", a.Mul(b.Add(c)), "a * (b + c)"},
+		{"left-assoc sub", a.Sub(b).Sub(c), "a - b - c"},
+		{"right operand sub", a.Sub(b.Sub(c)), "a - (b - c)"},
+		{"not of and", Not(a.And(b)), "!(a && b)"},
+		{"and inside or", a.And(b).Or(c), "a && b || c"},
+		{"or inside and", a.Or(b).And(c), "(a || b) && c"},
+		{"nested conditional", Cond(a.Or(b), Num(1), Cond(c, Num(2), Num(3))), "a || b ? 1 : c ? 2 : 3"},
+		{"conditional test", Cond(Cond(a, b, c), Num(1), Num(2)), "(a ? b : c) ? 1 : 2"},
+		{"right-assoc assign", Ident("x").Assign(Ident("y").Assign(Num(1))), "x = y = 1"},
+		{"raw as object", Raw("a, b").Dot("c"), "(a, b).c"},
+		{"raw as operand", Raw("a + b").Mul(Num(2)), "(a + b) * 2"},
+		{"raw as argument", Ident("f").Call(Raw("'a:', b")), "f('a:', b)"},
+		{"number member", Num(2).Method("toString"), "(2).toString()"},
+		{"negative number member", Num(-1).Dot("toFixed"), "(-1).toFixed"},
+		{"new with call", New(Ident("Date").Call()), "new (Date())()"},
+		{"new", New(Ident("Foo"), Num(1), Str("x")), "new Foo(1, 'x')"},
+		{"invalid identifier member", Ident("o").Dot("class-name"), "o['class-name']"},
+		{"object keys", Obj(map[string]Expr{"a-b": Num(1), "c": Str("x")}), "{'a-b': 1, c: 'x'}"},
+		{"comparison in equality", a.Lt(b).Eq(Bool(true)), "a < b === true"},
+		{"typeof", Typeof(a.Dot("x")).Eq(Str("string")), "typeof a.x === 'string'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.expr.String(); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNegation(t *testing.T) {
+	tests := []struct {
+		expr Expr
+		want string
+	}{
+		{Neg(Num(-1)), "- -1"},
+		{Neg(Neg(Ident("x"))), "- -x"},
+		{Neg(Value(-3)), "- -3"},
+		{Neg(Num(1)), "-1"},
+		{Neg(Ident("a").Add(Ident("b"))), "-(a + b)"},
+	}
+	for _, tt := range tests {
+		if got := tt.expr.String(); got != tt.want {
+			t.Errorf("got %s, want %s", got, tt.want)
+		}
+	}
+}
+
+func TestExprAction(t *testing.T) {
+	if got := Obj(map[string]Expr{"a": Num(1)}).Action().Code; got != "({a: 1})" {
+		t.Errorf("object statement = %s, want ({a: 1})", got)
+	}
+	if got := Ident("x").Assign(Num(1)).Action().Code; got != "x = 1" {
+		t.Errorf("assignment statement = %s", got)
+	}
+}
+
+func TestToExpr(t *testing.T) {
+	type point struct{ X, Y int }
+	tests := []struct {
+		name string
+		in   any
+		want string
+	}{
+		{"string is code", "raw.code()", "raw.code()"},
+		{"nil", nil, "null"},
+		{"bool", true, "true"},
+		{"float", 3.5, "3.5"},
+		{"unsafe integer", uint64(1 << 60), `"1152921504606846976"`},
+		{"map", map[string]int{"b": 2, "a": 1}, `{"a":1,"b":2}`},
+		{"struct", point{1, 2}, `{"X":1,"Y":2}`},
+		{"slice escapes", []string{"</script>"}, `["\u003c/script\u003e"]`},
+		{"expr", Ident("x").Dot("y"), "x.y"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := code(tt.in); got != tt.want {
+				t.Errorf("code(%#v) = %s, want %s", tt.in, got, tt.want)
+			}
+		})
+	}
+
+	if got := literalOr("text").String(); got != "'text'" {
+		t.Errorf("literalOr(string) = %s", got)
+	}
+	if got := literalOr(5).String(); got != "5" {
+		t.Errorf("literalOr(int) = %s", got)
+	}
+}
@@ -0,0 +1,330 @@
+// fetch.go
+package jsdsl
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+
+	. "github.com/TimLai666/go-vdom/dom"
+)
+
+// JSON 是 As 的預設響應類型：以 response.json() 解析（204 No Content 時為 null）
+const JSON = JSONResponse
+
+// RawResponse 不解析響應，直接返回 Response 物件
+const RawResponse ResponseType = "response"
+
+// DefaultCSRFHeader 是 CSRF() 未指定名稱時使用的請求頭
+const DefaultCSRFHeader = "X-CSRF-Token"
+
+// fetchBuilder 用於構建 fetch 請求的流暢 API
+type fetchBuilder struct {
+	url        Expr
+	method     string
+	headers    map[string]Expr
+	body       Expr
+	hasBody    bool
+	timeout    int
+	retries    int
+	respType   ResponseType
+	controller string
+	signal     any
+	csrfHeader string
+	options    map[string]Expr
+
+	// SubmitForm 插入的處理：在使用者的 Then/Catch 之前處理錯誤，在請求結束時清理
+	errorHook   string
+	cleanupHook string
+
+	thenActions    []JSAction
+	catchErrorName string
+	catchActions   []JSAction
+	finallyActions []JSAction
+}
+
+// Fetch 創建一個 fetch 請求的構建器；url 為字串（輸出為字串字面量）或 Expr
+// 非 2xx 的響應會以 Error 拒絕，錯誤物件帶有 status 與 response 屬性
+//
+// 用法：
+//
+//	js.Fetch("/api/todos").
+//	    Method("POST").
+//	    JSON(map[string]any{"title": title}).
+//	    CSRF().
+//	    Timeout(5000).
+//	    Retry(2).
+//	    Then(js.Call("render", "data")).
+//	    Catch("err", js.Alert("err.message")).
+//	    End()
+//
+//	// 在 AsyncDo 中等待結果：
+//	js.AsyncDo(nil,
+//	    js.Fetch(js.Str("/api/items?q=").Add(js.Ident("q"))).Abortable("search").Await("items"),
+//	    js.Log("items.length"),
+//	)
+func Fetch(url any) *fetchBuilder {
+	return &fetchBuilder{url: literalOr(url), headers: map[string]Expr{}, options: map[string]Expr{}, respType: JSON}
+}
+
+// Method 設定 HTTP 方法
+func (fb *fetchBuilder) Method(method string) *fetchBuilder {
+	fb.method = strings.ToUpper(method)
+	return fb
+}
+
+// Header 設定請求頭；value 為字串（輸出為字串字面量）或 Expr
+func (fb *fetchBuilder) Header(name string, value any) *fetchBuilder {
+	fb.headers[name] = literalOr(value)
+	return fb
+}
+
+// JSON 以 JSON 傳送 Go 值（以 Value 轉換，Expr 則為任意表達式），並設定 Content-Type；未設定方法時使用 POST
+func (fb *fetchBuilder) JSON(v any) *fetchBuilder {
+	fb.headers["Content-Type"] = Str("application/json")
+	return fb.Body(Ident("JSON").Method("stringify", Value(v)))
+}
+
+// Body 設定請求主體；字串輸出為字串字面量，Expr 則為任意表達式（如 FormData）；未設定方法時使用 POST
+func (fb *fetchBuilder) Body(body any) *fetchBuilder {
+	fb.body = literalOr(body)
+	fb.hasBody = true
+	return fb
+}
+
+// Option 設定其他 fetch 選項（如 credentials、mode、cache）；value 為字串（輸出為字串字面量）或 Expr
+func (fb *fetchBuilder) Option(name string, value any) *fetchBuilder {
+	fb.options[name] = literalOr(value)
+	return fb
+}
+
+// CSRF 從 <meta name="csrf-token"> 讀取令牌並以 header（預設 X-CSRF-Token）送出；沒有 meta 時不送出
+func (fb *fetchBuilder) CSRF(header ...string) *fetchBuilder {
+	fb.csrfHeader = DefaultCSRFHeader
+	if len(header) > 0 && header[0] != "" {
+		fb.csrfHeader = header[0]
+	}
+	return fb
+}
+
+// Timeout 設定逾時（毫秒），逾時後中止請求（包含重試）
+func (fb *fetchBuilder) Timeout(ms int) *fetchBuilder {
+	fb.timeout = ms
+	return fb
+}
+
+// Retry 在網路錯誤或 5xx 響應時最多重試 n 次；被中止的請求不會重試
+func (fb *fetchBuilder) Retry(n int) *fetchBuilder {
+	fb.retries = n
+	return fb
+}
+
+// Abortable 以 name 記錄請求的 AbortController；同名的請求再次送出時，先中止尚未完成的前一個
+// 也可以用 AbortFetch(name) 主動中止
+func (fb *fetchBuilder) Abortable(name string) *fetchBuilder {
+	fb.controller = name
+	return fb
+}
+
+// Signal 使用外部的 AbortSignal（JavaScript 代碼字串或 Expr，如 "controller.signal"）
+func (fb *fetchBuilder) Signal(signal any) *fetchBuilder {
+	fb.signal = signal
+	return fb
+}
+
+// As 設定響應的解析方式：JSON（預設）、TextResponse、BlobResponse 或 RawResponse
+func (fb *fetchBuilder) As(responseType ResponseType) *fetchBuilder {
+	fb.respType = responseType
+	return fb
+}
+
+// Then 添加成功處理器，解析後的響應為 data
+func (fb *fetchBuilder) Then(actions ...JSAction) *fetchBuilder {
+	fb.thenActions = append(fb.thenActions, actions...)
+	return fb
+}
+
+// Catch 添加錯誤處理器；errorName 為錯誤物件的變數名稱（空字串時為 error）
+func (fb *fetchBuilder) Catch(errorName string, actions ...JSAction) *fetchBuilder {
+	fb.catchErrorName = errorName
+	fb.catchActions = actions
+	return fb
+}
+
+// Finally 添加無論成功或失敗都會執行的動作
+func (fb *fetchBuilder) Finally(actions ...JSAction) *fetchBuilder {
+	fb.finallyActions = append(fb.finallyActions, actions...)
+	return fb
+}
+
+// clone 返回構建器的副本；請求頭、選項與處理器各自複製，修改副本不會影響原本的構建器
+func (fb *fetchBuilder) clone() *fetchBuilder {
+	c := *fb
+	c.headers = maps.Clone(fb.headers)
+	c.options = maps.Clone(fb.options)
+	c.thenActions = slices.Clone(fb.thenActions)
+	c.catchActions = slices.Clone(fb.catchActions)
+	c.finallyActions = slices.Clone(fb.finallyActions)
+	return &c
+}
+
+// End 結束構建，返回送出請求的語句
+func (fb *fetchBuilder) End() JSAction {
+	return JSAction{Code: fb.Expr().String()}
+}
+
+// Await 返回 const varName=await ...，用於 AsyncDo 或 AsyncFn 中；varName 為空時只等待請求完成
+// 有 Catch 時錯誤已被處理，varName 的值為 undefined
+func (fb *fetchBuilder) Await(varName string) JSAction {
+	if varName == "" {
+		return Await(fb.Expr()).Action()
+	}
+	return Const(varName, Await(fb.Expr()))
+}
+
+// Expr 返回請求的 Promise 表達式，其值為 Then 處理後的 data
+// 網址、請求頭、body 與 Signal 作為參數在呼叫處求值，送出請求的內部變數不會遮蔽呼叫處的名稱；
+// Then/Catch/Finally 接在返回的 Promise 之後，同樣在呼叫處的作用域中執行
+// 各處理器為箭頭函數，內聯事件處理器中的 this 與 event 保持可用
+func (fb *fetchBuilder) Expr() Expr {
+	var sb strings.Builder
+	aborts := fb.timeout > 0 || fb.controller != "" || fb.signal != nil
+	if fb.signal != nil {
+		sb.WriteString("((u,o,s)=>{")
+	} else {
+		sb.WriteString("((u,o)=>{")
+	}
+	if aborts {
+		sb.WriteString("var c=new AbortController();o.signal=c.signal;")
+	}
+	if fb.signal != nil {
+		sb.WriteString("if(s){if(s.aborted)c.abort();else s.addEventListener('abort',function(){c.abort()})}")
+	}
+	if fb.controller != "" {
+		fmt.Fprintf(&sb, "var g=%s,f=g.fetches||(g.fetches={}),k=%s;if(f[k])f[k].abort();f[k]=c;", timerRegistry, Str(fb.controller))
+	}
+	if fb.timeout > 0 {
+		fmt.Fprintf(&sb, "var t=setTimeout(function(){c.abort()},%d);", fb.timeout)
+	}
+	if fb.csrfHeader != "" {
+		fmt.Fprintf(&sb, "var m=document.querySelector('meta[name=\"csrf-token\"]');if(m)o.headers[%s]=m.content;", Str(fb.csrfHeader))
+	}
+
+	// attempt(n)：送出請求，n 為剩餘的重試次數
+	sb.WriteString("function a(n){return fetch(u,o).then(function(r){")
+	sb.WriteString("if(!r.ok){if(n>0&&r.status>=500)return a(n-1);var e=new Error('HTTP '+r.status+' '+r.statusText);e.status=r.status;e.response=r;throw e}")
+	sb.WriteString("return " + fb.parseResponse() + "}")
+	sb.WriteString(",function(e){if(n>0&&e.name!=='AbortError')return a(n-1);throw e})}")
+
+	fmt.Fprintf(&sb, "return a(%d)", fb.retries)
+	if fb.timeout > 0 || fb.controller != "" {
+		sb.WriteString(".finally(()=>{")
+		if fb.timeout > 0 {
+			sb.WriteString("clearTimeout(t)")
+		}
+		if fb.timeout > 0 && fb.controller != "" {
+			sb.WriteString(";")
+		}
+		if fb.controller != "" {
+			sb.WriteString("if(f[k]===c)delete f[k]")
+		}
+		sb.WriteString("})")
+	}
+	sb.WriteString("})(" + fb.url.String() + "," + fb.initObject())
+	if fb.signal != nil {
+		sb.WriteString("," + code(fb.signal))
+	}
+	sb.WriteString(")")
+
+	if fb.cleanupHook != "" {
+		sb.WriteString(".finally(()=>{" + fb.cleanupHook + "})")
+	}
+	if fb.errorHook != "" {
+		sb.WriteString(".catch(e=>" + fb.errorHook + ")")
+	}
+	if len(fb.thenActions) > 0 {
+		sb.WriteString(".then(data=>{" + resolveLocals(block(append(append([]JSAction{}, fb.thenActions...), Return("data"))), "data") + "})")
+	}
+	if len(fb.catchActions) > 0 {
+		errName := fb.catchErrorName
+		if errName == "" {
+			errName = "error"
+		}
+		sb.WriteString(".catch((" + errName + ")=>{" + resolveLocals(block(fb.catchActions), errName) + "})")
+	}
+	if len(fb.finallyActions) > 0 {
+		sb.WriteString(".finally(()=>{" + resolveLocals(block(fb.finallyActions)) + "})")
+	}
+	return Expr{literal{code: sb.String(), p: precCall}}
+}
+
+// initObject 返回傳給 fetch 的選項物件字面量
+func (fb *fetchBuilder) initObject() string {
+	var sb strings.Builder
+	sb.WriteString("{method:" + Str(fb.methodOrDefault()).String() + ",headers:" + fb.headersObject())
+	if fb.hasBody {
+		sb.WriteString(",body:" + fb.body.String())
+	}
+	for _, name := range sortedExprKeys(fb.options) {
+		sb.WriteString("," + propertyKey(name) + ":" + fb.options[name].String())
+	}
+	sb.WriteString("}")
+	return sb.String()
+}
+
+func (fb *fetchBuilder) methodOrDefault() string {
+	switch {
+	case fb.method != "":
+		return fb.method
+	case fb.hasBody:
+		return "POST"
+	default:
+		return "GET"
+	}
+}
+
+func (fb *fetchBuilder) headersObject() string {
+	names := sortedExprKeys(fb.headers)
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = Str(name).String() + ":" + fb.headers[name].String()
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+func sortedExprKeys(m map[string]Expr) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// propertyKey 返回物件字面量的鍵：識別字原樣輸出，其他加引號
+func propertyKey(name string) string {
+	if identPattern.MatchString(name) {
+		return name
+	}
+	return Str(name).String()
+}
+
+func (fb *fetchBuilder) parseResponse() string {
+	switch fb.respType {
+	case TextResponse:
+		return "r.text()"
+	case BlobResponse:
+		return "r.blob()"
+	case RawResponse:
+		return "r"
+	default:
+		return "r.status===204?null:r.json()"
+	}
+}
+
+// AbortFetch 中止以 Abortable(name) 送出且尚未完成的請求
+func AbortFetch(name string) JSAction {
+	return JSAction{Code: fmt.Sprintf("(function(g){var f=g&&g.fetches;if(f&&f[%[1]s])f[%[1]s].abort()})(window.__gvd)", Str(name))}
+}
@@ -0,0 +1,110 @@
+// fetch_test.go
+package jsdsl
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+// checkFragments 檢查每段代碼都包含指定的片段
+func checkFragments(t *testing.T, name, code string, fragments ...string) {
+	t.Helper()
+	for _, f := range fragments {
+		if !strings.Contains(code, f) {
+			t.Errorf("%s: missing %s\nin %s", name, f, code)
+		}
+	}
+}
+
+func TestFetchBuilder(t *testing.T) {
+	get := Fetch("/api/items").End().Code
+	// 網址與選項作為參數在呼叫處求值
+	if !strings.HasPrefix(get, "((u,o)=>{") || !strings.HasSuffix(get, "})('/api/items',{method:'GET',headers:{}})") {
+		t.Errorf("default GET: %s", get)
+	}
+	checkFragments(t, "default GET", get, "return fetch(u,o)", "return r.status===204?null:r.json()", "return a(0)")
+
+	todos := Fetch("/api/todos").JSON(map[string]any{"title": "a<b"}).CSRF().Then(Call("render", "data")).Catch("err", Alert("err.message")).Finally(Log("'done'")).End().Code
+	checkFragments(t, "JSON and CSRF", todos,
+		`if(m)o.headers['X-CSRF-Token']=m.content`,
+		`{method:'POST',headers:{'Content-Type':'application/json'},body:JSON.stringify({"title":"a\u003cb"})}`,
+	)
+	// 使用者的處理器接在請求之後，不在內部函數中
+	if !strings.HasSuffix(todos, `}).then(data=>{render(data);return data}).catch((err)=>{alert(err.message)}).finally(()=>{console.log('done')})`) {
+		t.Errorf("handlers should follow the request: %s", todos)
+	}
+
+	slow := Fetch("/api/slow").Method("put").Timeout(5000).Retry(2).Option("credentials", "include").Option("x-y", Ident("z")).As(TextResponse).End().Code
+	checkFragments(t, "timeout, retry and options", slow,
+		"o.signal=c.signal",
+		"setTimeout(function(){c.abort()},5000)",
+		"return a(2).finally(()=>{clearTimeout(t)})",
+		"return r.text()",
+		`{method:'PUT',headers:{},credentials:'include','x-y':z}`,
+	)
+
+	search := Fetch(Str("/api/items?q=").Add(Ident("q"))).Abortable("search").Await("items").Code
+	if !strings.HasPrefix(search, "const items=await ((u,o)=>{") {
+		t.Errorf("abortable await: %s", search)
+	}
+	checkFragments(t, "abortable", search,
+		"k='search';if(f[k])f[k].abort();f[k]=c;",
+		"finally(()=>{if(f[k]===c)delete f[k]})",
+		"})('/api/items?q=' + q,",
+	)
+
+	signal := Fetch("/f").Signal("ctl.signal").As(RawResponse).Await("").Code
+	if !strings.HasPrefix(signal, "await ((u,o,s)=>{") || !strings.HasSuffix(signal, ",ctl.signal)") {
+		t.Errorf("external signal should be passed as an argument: %s", signal)
+	}
+	checkFragments(t, "external signal", signal, "if(s){if(s.aborted)c.abort();", "return r}")
+
+	if got, want := AbortFetch("search").Code, `(function(g){var f=g&&g.fetches;if(f&&f['search'])f['search'].abort()})(window.__gvd)`; got != want {
+		t.Errorf("AbortFetch = %s, want %s", got, want)
+	}
+}
+
+// FetchRequest 改由 Fetch 構建器輸出後，字串選項仍以字串字面量輸出
+func TestFetchRequest(t *testing.T) {
+	login := FetchRequest("/api/login", append(WithJSON(`{"u":1}`), WithMethod("post"), WithHeader("X-A", "b"), FetchOption{Key: "mode", Value: "cors"})...).Code
+	checkFragments(t, "json, method, header and option", login,
+		`})('/api/login',{method:'POST',headers:{'Content-Type':'application/json','X-A':'b'},body:'{"u":1}',mode:'cors'})`)
+
+	form := FetchRequest("/api/form", WithFormData(map[string]string{"b": "2&", "a": "1"})...).Code
+	checkFragments(t, "form data", form,
+		`headers:{'Content-Type':'application/x-www-form-urlencoded'},body:'a=1\u0026b=2%26'`)
+}
+
+// runJS 以 node 執行腳本並返回去除首尾空白的輸出；沒有安裝 node 時跳過測試
+func runJS(t *testing.T, script string) string {
+	t.Helper()
+	node, err := exec.LookPath("node")
+	if err != nil {
+		t.Skip("node is not installed")
+	}
+	cmd := exec.Command(node, "-")
+	cmd.Stdin = strings.NewReader(script)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("node: %v\n%s", err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func TestFetchDoesNotShadowCallerNames(t *testing.T) {
+	// 呼叫處的變數與內部使用的名稱相同；網址、主體、請求頭與處理器都必須取得呼叫處的值
+	request := Fetch(Ident("a")).Body(Ident("o")).Header("X-P", Ident("p")).CSRF().Timeout(1000).Abortable("x").
+		Then(Call("done", "[a,o,p,c,t,g,f,k,m,u,data]")).
+		Catch("e", Call("done", "String(e)"))
+	script := `var window=globalThis,document={querySelector(){return {content:'tok'}}};
+function fetch(url,init){return Promise.resolve({ok:true,status:200,json(){return Promise.resolve({url:url,body:init.body,p:init.headers['X-P'],csrf:init.headers['X-CSRF-Token']})}})}
+function done(v){console.log(JSON.stringify(v))}
+var a='/x',o='B',p='P',c='C',t='T',g='G',f='F',k='K',m='M',u='U';
+` + request.End().Code + ";"
+
+	want := `["/x","B","P","C","T","G","F","K","M","U",{"url":"/x","body":"B","p":"P","csrf":"tok"}]`
+	if got := runJS(t, script); got != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+}
@@ -0,0 +1,60 @@
+// form.go
+package jsdsl
+
+import (
+	. "github.com/TimLai666/go-vdom/dom"
+)
+
+// formVar 是 SubmitForm 中保存表單元素的變數名稱
+const formVar = "gvdForm"
+
+// FormData 返回 new FormData(form)；form 為 Elem、JavaScript 代碼字串或 Expr
+//
+// 用法：js.Fetch("/upload").Body(js.FormData(js.El("#avatar-form")))
+func FormData(form any) Expr {
+	return New(Ident("FormData"), toExpr(form))
+}
+
+// FormJSON 將表單序列化為物件（由客戶端運行時提供）；form 為 Elem、JavaScript 代碼字串或 Expr
+// name="a.b" 成為巢狀物件 {a:{b:...}}，重複的名稱（如多選的 checkbox）或以 [] 結尾的名稱成為陣列
+//
+// 用法：js.Fetch("/api/profile").JSON(js.FormJSON(js.This()))
+func FormJSON(form any) Expr {
+	return gvdRuntime.Method("formJSON", toExpr(form))
+}
+
+// SubmitForm 送出表單：請求期間停用送出按鈕，結束後恢復，並清除上次的驗證錯誤
+// request 未設定主體時以 FormJSON 送出表單內容
+// 422 響應的 JSON（{ 欄位: 訊息 } 或 { errors: {...} }）顯示在對應欄位的 TextField / Dropdown 說明文字，
+// 或 data-gvd-error-for="欄位名稱" 的元素中；錯誤仍會傳給 request 的 Catch，err.errors 為攤平後的錯誤
+// request 沒有 Catch 時，除驗證錯誤外的錯誤輸出到 console；request 本身不會被修改
+//
+// 用法：
+//
+//	Form(Props{"onSubmit": js.Do(nil,
+//	    JSAction{Code: "event.preventDefault()"},
+//	    js.SubmitForm(js.This(), js.Fetch("/api/signup").Then(js.Redirect("/welcome"))),
+//	)},
+//	    TextField(Props{"name": "email", "label": "電子郵件"}),
+//	    Button(Props{"type": "submit"}, "註冊"),
+//	)
+func SubmitForm(form any, request *fetchBuilder) JSAction {
+	f := Ident(formVar)
+	// 在副本上加入表單的處理，request 可以繼續使用或再次傳給 SubmitForm
+	request = request.clone()
+	if !request.hasBody {
+		request.JSON(FormJSON(f))
+	}
+	request.errorHook = gvdRuntime.Method("formError", f, Ident("e")).String()
+	request.cleanupHook = gvdRuntime.Method("formBusy", f, Bool(false)).String()
+	if len(request.catchActions) == 0 {
+		request.Catch("error", If("error.status!==422", CallMethod("console", "error", "error")).End())
+	}
+	body := block([]JSAction{
+		Const(formVar, toExpr(form)),
+		gvdRuntime.Method("clearFormErrors", f).Action(),
+		gvdRuntime.Method("formBusy", f, Bool(true)).Action(),
+		Return(request.Expr()),
+	})
+	return JSAction{Code: "(()=>{" + body + "})()"}
+}
@@ -0,0 +1,57 @@
+// form_test.go
+package jsdsl
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSubmitForm(t *testing.T) {
+	// 沒有主體時以 FormJSON 送出；沒有 Catch 時只輸出非 422 的錯誤
+	code := SubmitForm(This(), Fetch("/api/signup").Then(Redirect("/welcome"))).Code
+	if !strings.HasPrefix(code, "(()=>{const gvdForm=this;window.__gvd.clearFormErrors(gvdForm);window.__gvd.formBusy(gvdForm, true);return ") {
+		t.Errorf("form should be cleared and marked busy before the request: %s", code)
+	}
+	checkFragments(t, "default body", code,
+		`body:JSON.stringify(window.__gvd.formJSON(gvdForm))`,
+		`.catch((error)=>{if(error.status!==422){console.error(error)}})`,
+	)
+	// 表單的清理與錯誤處理在使用者的 Then 之前執行
+	hooks := `.finally(()=>{window.__gvd.formBusy(gvdForm, false)}).catch(e=>window.__gvd.formError(gvdForm, e)).then(data=>{location.href = '/welcome';return data})`
+	checkFragments(t, "hook order", code, hooks)
+
+	// 保留 request 的主體與 Catch
+	code = SubmitForm(El("#f"), Fetch("/up").Body(FormData(Ident("gvdForm"))).Catch("err", Alert("err.message"))).Code
+	checkFragments(t, "custom body and catch", code,
+		`const gvdForm=document.querySelector('#f')`,
+		`{method:'POST',headers:{},body:new FormData(gvdForm)}`,
+		`.catch((err)=>{alert(err.message)})`,
+	)
+	if strings.Contains(code, "console.error") || strings.Contains(code, "formJSON") {
+		t.Errorf("custom body and Catch should be kept: %s", code)
+	}
+
+	if got := FormJSON(El("#f")).String(); got != `window.__gvd.formJSON(document.querySelector('#f'))` {
+		t.Errorf("FormJSON = %s", got)
+	}
+	if got := FormData("this").String(); got != `new FormData(this)` {
+		t.Errorf("FormData = %s", got)
+	}
+}
+
+func TestSubmitFormDoesNotModifyRequest(t *testing.T) {
+	request := Fetch("/api/signup").Header("X-A", "1").Then(Redirect("/welcome"))
+	before := request.End().Code
+
+	first := SubmitForm(This(), request).Code
+	if got := request.End().Code; got != before {
+		t.Fatalf("SubmitForm modified the request:\ngot  %s\nwant %s", got, before)
+	}
+	if second := SubmitForm(This(), request).Code; second != first {
+		t.Errorf("SubmitForm output changed on reuse:\nfirst  %s\nsecond %s", first, second)
+	}
+	request.Catch("err", Alert("err.message"))
+	if got := SubmitForm(This(), request).Code; !strings.Contains(got, ".catch((err)=>{alert(err.message)})") || strings.Contains(got, "console.error") {
+		t.Errorf("Catch added after SubmitForm should replace the default handler: %s", got)
+	}
+}
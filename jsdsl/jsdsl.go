@@ -2,7 +2,7 @@ package jsdsl
 
 import (
 	"fmt"
-	"math/rand"
+	"net/url"
 	"strings"
 
 	. "github.com/TimLai666/go-vdom/dom"
@@ -18,29 +18,34 @@ func V(varName string) variable {
 	return variable{Name: varName}
 }
 
-// SetHTML 設定 innerHTML
-func (v variable) SetHTML(html string) JSAction {
-	return JSAction{Code: fmt.Sprintf("%s.innerHTML = %s", v.Name, html)}
+// Expr 返回變數的表達式
+func (v variable) Expr() Expr {
+	return Ident(v.Name)
 }
 
-// SetText 設定 innerText
-func (v variable) SetText(text string) JSAction {
-	return JSAction{Code: fmt.Sprintf("%s.innerText = %s", v.Name, text)}
+// SetHTML 設定 innerHTML；html 為 JavaScript 代碼字串或 Expr
+func (v variable) SetHTML(html any) JSAction {
+	return JSAction{Code: fmt.Sprintf("%s.innerHTML = %s", v.Name, code(html))}
 }
 
-// AddClass 新增 class
-func (v variable) AddClass(class string) JSAction {
-	return JSAction{Code: fmt.Sprintf("%s.classList.add(%s)", v.Name, class)}
+// SetText 設定 innerText；text 為 JavaScript 代碼字串或 Expr
+func (v variable) SetText(text any) JSAction {
+	return JSAction{Code: fmt.Sprintf("%s.innerText = %s", v.Name, code(text))}
 }
 
-// RemoveClass 移除 class
-func (v variable) RemoveClass(class string) JSAction {
-	return JSAction{Code: fmt.Sprintf("%s.classList.remove(%s)", v.Name, class)}
+// AddClass 新增 class；class 為 JavaScript 代碼字串或 Expr（字面量請用 Str）
+func (v variable) AddClass(class any) JSAction {
+	return JSAction{Code: fmt.Sprintf("%s.classList.add(%s)", v.Name, code(class))}
 }
 
-// CallMethod 呼叫任意方法
-func (v variable) CallMethod(method string, args ...string) JSAction {
-	return JSAction{Code: fmt.Sprintf("%s.%s(%s)", v.Name, method, strings.Join(args, ", "))}
+// RemoveClass 移除 class；class 為 JavaScript 代碼字串或 Expr（字面量請用 Str）
+func (v variable) RemoveClass(class any) JSAction {
+	return JSAction{Code: fmt.Sprintf("%s.classList.remove(%s)", v.Name, code(class))}
+}
+
+// CallMethod 呼叫任意方法；參數為 JavaScript 代碼字串或 Expr
+func (v variable) CallMethod(method string, args ...any) JSAction {
+	return CallMethod(v.Name, method, args...)
 }
 
 // VRef 代表一個 JS 變數（Variable Reference）for 只取變數名
@@ -74,14 +79,24 @@ func Els(selector string) ElemList {
 }
 
 func (e Elem) Ref() string {
+	return e.Expr().String()
+}
+
+// Expr 返回元素的表達式：變數名稱或 document.querySelector(selector)
+func (e Elem) Expr() Expr {
 	if e.VarName != "" {
-		return e.VarName
+		return Ident(e.VarName)
 	}
-	return fmt.Sprintf("document.querySelector('%s')", e.Selector)
+	return Ident("document").Method("querySelector", Str(e.Selector))
 }
 
 func (e ElemList) Ref() string {
-	return fmt.Sprintf("document.querySelectorAll('%s')", e.Selector)
+	return e.Expr().String()
+}
+
+// Expr 返回 document.querySelectorAll(selector) 表達式
+func (e ElemList) Expr() Expr {
+	return Ident("document").Method("querySelectorAll", Str(e.Selector))
 }
 
 // Fn 創建一個函數，支援傳入參數
@@ -97,16 +112,18 @@ func Fn(params []string, actions ...JSAction) JSAction {
 	sb.WriteString(fmt.Sprintf("(%s)=>{", paramsStr))
 
 	// 添加函數體
+	var body strings.Builder
 	for i, action := range actions {
 		if i > 0 {
-			sb.WriteString(";")
+			body.WriteString(";")
 		}
 		code := strings.TrimSpace(action.Code)
 		if strings.HasSuffix(code, ";") {
 			code = strings.TrimSuffix(code, ";")
 		}
-		sb.WriteString(code)
+		body.WriteString(code)
 	}
+	sb.WriteString(resolveLocals(body.String(), params...))
 
 	sb.WriteString("}")
 	return JSAction{Code: sb.String()}
@@ -125,56 +142,42 @@ func AsyncFn(params []string, actions ...JSAction) JSAction {
 	sb.WriteString(fmt.Sprintf("async (%s)=>{", paramsStr))
 
 	// 添加函數體
+	var body strings.Builder
 	for i, action := range actions {
 		if i > 0 {
-			sb.WriteString(";")
+			body.WriteString(";")
 		}
 		code := strings.TrimSpace(action.Code)
 		if strings.HasSuffix(code, ";") {
 			code = strings.TrimSuffix(code, ";")
 		}
-		sb.WriteString(code)
+		body.WriteString(code)
 	}
+	sb.WriteString(resolveLocals(body.String(), params...))
 
 	sb.WriteString("}")
 	return JSAction{Code: sb.String()}
 }
 
 // Call 調用一個函數，傳入參數
+// 函數與參數可為 JavaScript 代碼字串、JSAction 或 Expr；Go 的數字與布林值輸出為字面量
 func Call(fnExpr any, args ...any) JSAction {
 	var processedArgs []string
-	var fnExprStr string
-
-	// 處理函數表達式
-	switch v := fnExpr.(type) {
-	case string:
-		fnExprStr = v
-	case JSAction:
-		fnExprStr = v.Code
-	default:
-		fnExprStr = fmt.Sprintf("%v", fnExpr)
-	}
-
-	// 處理參數
 	for _, arg := range args {
-		switch v := arg.(type) {
-		case string:
-			processedArgs = append(processedArgs, v)
-		case JSAction:
-			processedArgs = append(processedArgs, v.Code)
-		default:
-			processedArgs = append(processedArgs, fmt.Sprintf("%v", v))
-		}
+		processedArgs = append(processedArgs, code(arg))
 	}
 
 	argsStr := strings.Join(processedArgs, ", ")
-	return JSAction{Code: fmt.Sprintf("%s(%s)", fnExprStr, argsStr)}
+	return JSAction{Code: fmt.Sprintf("%s(%s)", code(fnExpr), argsStr)}
 }
 
 // Method 調用對象的方法，更符合直觀的呼叫方式
 // 用法：Method("object", "methodName", arg1, arg2, ...)
-func CallMethod(objExpr string, methodName string, args ...any) JSAction {
-	return Call(fmt.Sprintf("%s.%s", objExpr, methodName), args...)
+func CallMethod(objExpr any, methodName string, args ...any) JSAction {
+	if e, ok := objExpr.(Expr); ok {
+		return Call(e.Dot(methodName), args...)
+	}
+	return Call(fmt.Sprintf("%s.%s", code(objExpr), methodName), args...)
 }
 
 func DomReady(actions ...JSAction) JSAction {
@@ -182,38 +185,47 @@ func DomReady(actions ...JSAction) JSAction {
 %s);`, indent(Fn(nil, actions...).Code, "  "))}
 }
 
-func (el Elem) SetText(text string) JSAction {
-	return JSAction{Code: fmt.Sprintf(`%s.innerText = %s`, el.Ref(), text)}
+// SetText 設定 innerText；text 為 JavaScript 代碼字串或 Expr（字面量請用 Str）
+func (el Elem) SetText(text any) JSAction {
+	return JSAction{Code: fmt.Sprintf(`%s.innerText = %s`, el.Ref(), code(text))}
 }
 
-func (el Elem) SetHTML(html string) JSAction {
-	return JSAction{Code: fmt.Sprintf(`%s.innerHTML = %s`, el.Ref(), html)}
+// SetHTML 設定 innerHTML；html 為 JavaScript 代碼字串或 Expr
+func (el Elem) SetHTML(html any) JSAction {
+	return JSAction{Code: fmt.Sprintf(`%s.innerHTML = %s`, el.Ref(), code(html))}
 }
 
-func (el Elem) AddClass(class string) JSAction {
-	return JSAction{Code: fmt.Sprintf(`%s.classList.add('%s')`, el.Ref(), class)}
+// AddClass 新增 class；字串參數視為 class 名稱（輸出為字串字面量），Expr 則為任意表達式
+func (el Elem) AddClass(class any) JSAction {
+	return JSAction{Code: fmt.Sprintf(`%s.classList.add(%s)`, el.Ref(), literalOr(class))}
 }
 
-func (el Elem) RemoveClass(class string) JSAction {
-	return JSAction{Code: fmt.Sprintf(`%s.classList.remove('%s')`, el.Ref(), class)}
+// RemoveClass 移除 class；字串參數視為 class 名稱（輸出為字串字面量），Expr 則為任意表達式
+func (el Elem) RemoveClass(class any) JSAction {
+	return JSAction{Code: fmt.Sprintf(`%s.classList.remove(%s)`, el.Ref(), literalOr(class))}
 }
 
-func Log(msg string) JSAction {
-	return JSAction{Code: fmt.Sprintf(`console.log(%s)`, msg)}
+// Log 輸出 console.log(msg)
+// msg 為 JavaScript 代碼字串（例如 "'訊息:', data"）或 Expr（例如 Str("它's 安全")）
+func Log(msg any) JSAction {
+	return JSAction{Code: fmt.Sprintf(`console.log(%s)`, code(msg))}
 }
 
-func Redirect(url string) JSAction {
-	return JSAction{Code: fmt.Sprintf(`location.href = '%s'`, url)}
+// Redirect 導向 url；字串參數視為網址（輸出為字串字面量），Expr 則為任意表達式
+func Redirect(url any) JSAction {
+	return JSAction{Code: fmt.Sprintf(`location.href = %s`, literalOr(url))}
 }
 
+// OnClick 綁定不帶事件物件的 click 處理器；需要事件物件或監聽選項時使用 On
 func (el Elem) OnClick(action JSAction) JSAction {
 	return JSAction{Code: fmt.Sprintf(`%s.addEventListener('click', function() {
 %s
 });`, el.Ref(), indent(action.Code, "  "))}
 }
 
-func Alert(jsExpr string) JSAction {
-	return JSAction{Code: fmt.Sprintf(`alert(%s)`, jsExpr)}
+// Alert 輸出 alert(jsExpr)；jsExpr 為 JavaScript 代碼字串或 Expr
+func Alert(jsExpr any) JSAction {
+	return JSAction{Code: fmt.Sprintf(`alert(%s)`, code(jsExpr))}
 }
 
 func (el Elem) InnerText() string {
@@ -230,7 +242,7 @@ func (el Elem) InnerHTML() string {
 // - itemVar: 項目變數名稱（如 "item", "user"）
 // - actions: 對每個項目執行的動作
 // 用法：js.ForEachJS("items", "item", js.Log("item"))
-func ForEachJS(arrayExpr string, itemVar string, actions ...JSAction) JSAction {
+func ForEachJS(arrayExpr any, itemVar string, actions ...JSAction) JSAction {
 	var sb strings.Builder
 	for _, a := range actions {
 		line := strings.TrimSpace(a.Code)
@@ -242,7 +254,7 @@ func ForEachJS(arrayExpr string, itemVar string, actions ...JSAction) JSAction {
 
 	return JSAction{
 		Code: fmt.Sprintf(`%s.forEach(function(%s) {
-%s});`, arrayExpr, itemVar, indent(sb.String(), "  ")),
+%s});`, code(arrayExpr), itemVar, indent(resolveLocals(sb.String(), itemVar), "  ")),
 	}
 }
 
@@ -252,7 +264,7 @@ func ForEachJS(arrayExpr string, itemVar string, actions ...JSAction) JSAction {
 // - itemVar: 項目變數名稱
 // - indexVar: 索引變數名稱
 // - actions: 對每個項目執行的動作
-func ForEachWithIndexJS(arrayExpr string, itemVar string, indexVar string, actions ...JSAction) JSAction {
+func ForEachWithIndexJS(arrayExpr any, itemVar string, indexVar string, actions ...JSAction) JSAction {
 	var sb strings.Builder
 	for _, a := range actions {
 		line := strings.TrimSpace(a.Code)
@@ -264,18 +276,18 @@ func ForEachWithIndexJS(arrayExpr string, itemVar string, indexVar string, actio
 
 	return JSAction{
 		Code: fmt.Sprintf(`%s.forEach(function(%s, %s) {
-%s});`, arrayExpr, itemVar, indexVar, indent(sb.String(), "  ")),
+%s});`, code(arrayExpr), itemVar, indexVar, indent(resolveLocals(sb.String(), itemVar, indexVar), "  ")),
 	}
 }
 
 // ForEachElement 遍歷 DOM 元素列表（保留向後兼容）
 // 這是專門用於 DOM 元素操作的版本
-func ForEachElement(arrayExpr string, fn func(el Elem) JSAction) JSAction {
+func ForEachElement(arrayExpr any, fn func(el Elem) JSAction) JSAction {
 	el := "el"
 	return JSAction{
 		Code: fmt.Sprintf(`%s.forEach(function(%s) {
 %s
-});`, arrayExpr, el, indent(fn(Elem{VarName: el}).Code, "  ")),
+});`, code(arrayExpr), el, indent(resolveLocals(fn(Elem{VarName: el}).Code, el), "  ")),
 	}
 }
 
@@ -300,30 +312,14 @@ func escapeJS(s string) string {
 	return s
 }
 
+// Let 宣告變數；value 為 JavaScript 代碼字串、JSAction 或 Expr
 func Let(varName string, value any) JSAction {
-	valueStr := ""
-	switch v := value.(type) {
-	case string:
-		valueStr = v
-	case JSAction:
-		valueStr = v.Code
-	default:
-		valueStr = fmt.Sprintf("%v", v)
-	}
-	return JSAction{Code: fmt.Sprintf("let %s=%s", varName, valueStr)}
+	return JSAction{Code: fmt.Sprintf("let %s=%s", varName, code(value))}
 }
 
+// Const 宣告常數；value 為 JavaScript 代碼字串、JSAction 或 Expr
 func Const(varName string, value any) JSAction {
-	valueStr := ""
-	switch v := value.(type) {
-	case string:
-		valueStr = v
-	case JSAction:
-		valueStr = v.Code
-	default:
-		valueStr = fmt.Sprintf("%v", v)
-	}
-	return JSAction{Code: fmt.Sprintf("const %s=%s", varName, valueStr)}
+	return JSAction{Code: fmt.Sprintf("const %s=%s", varName, code(value))}
 }
 
 // FetchOption 代表一個 fetch 請求的選項
@@ -353,6 +349,7 @@ func WithContentType(contentType string) FetchOption {
 }
 
 // WithJSON 設定 Content-Type 為 application/json 並且將主體設定為 JSON 字符串
+// 傳送 Go 值時請使用 Fetch(url).JSON(v)，由構建器負責序列化
 func WithJSON(jsonObject string) []FetchOption {
 	return []FetchOption{
 		WithContentType("application/json"),
@@ -361,12 +358,13 @@ func WithJSON(jsonObject string) []FetchOption {
 }
 
 // WithFormData 設定 Content-Type 為 application/x-www-form-urlencoded
+// 鍵與值以 URL 編碼，輸出依鍵排序
 func WithFormData(formData map[string]string) []FetchOption {
-	var values []string
+	values := url.Values{}
 	for key, value := range formData {
-		values = append(values, fmt.Sprintf("%s=%s", key, value))
+		values.Set(key, value)
 	}
-	formBody := strings.Join(values, "&")
+	formBody := values.Encode()
 
 	return []FetchOption{
 		WithContentType("application/x-www-form-urlencoded"),
@@ -383,12 +381,28 @@ const (
 	BlobResponse ResponseType = "blob"
 )
 
-// FetchRequest 創建一個通用的 fetch 請求
+// FetchRequest 創建一個通用的 fetch 請求，以 JSON 解析響應
+// 新代碼建議直接使用 Fetch 構建器
 func FetchRequest(url string, options ...FetchOption) JSAction {
-	return buildFetch(url, JSONResponse, "", "", "", options...)
+	fb := Fetch(url)
+	for _, opt := range options {
+		switch {
+		case opt.Key == "method":
+			fb.Method(opt.Value)
+		case opt.Key == "body":
+			fb.Body(opt.Value)
+		case strings.HasPrefix(opt.Key, "headers."):
+			fb.Header(strings.TrimPrefix(opt.Key, "headers."), opt.Value)
+		default:
+			fb.Option(opt.Key, opt.Value)
+		}
+	}
+	return fb.End()
 }
 
 // WithThen 添加 then 處理器
+//
+// Deprecated: FetchRequest 無法接收此返回值，請使用 Fetch(url).Then(...)
 func WithThen(thenCodes ...interface{}) JSAction {
 	var sb strings.Builder
 
@@ -422,109 +436,12 @@ func WithThen(thenCodes ...interface{}) JSAction {
 // This placeholder comment remains to indicate the old API has been intentionally removed.
 
 // WithResponseType 設定響應類型
+//
+// Deprecated: FetchRequest 無法接收此返回值，請使用 Fetch(url).As(responseType)
 func WithResponseType(responseType ResponseType) JSAction {
 	return JSAction{Code: fmt.Sprintf("response_type:%s", string(responseType))}
 }
 
-// 處理多種可能的代碼輸入類型
-func processCodeInput(code interface{}) string {
-	switch v := code.(type) {
-	case string:
-		return v
-	case JSAction:
-		return v.Code
-	default:
-		return fmt.Sprintf("%v", code)
-	}
-}
-
-// 構建完整的 fetch 請求
-func buildFetch(url string, responseType ResponseType, then interface{}, catch interface{}, finally interface{}, options ...FetchOption) JSAction {
-	// 處理輸入代碼
-	thenStr := ""
-	if then != nil {
-		thenStr = processCodeInput(then)
-	}
-
-	catchStr := ""
-	if catch != nil {
-		catchStr = processCodeInput(catch)
-	}
-
-	finallyStr := ""
-	if finally != nil {
-		finallyStr = processCodeInput(finally)
-	}
-
-	var optsBuilder strings.Builder
-	optsBuilder.WriteString("{\n")
-
-	// 處理所有的選項
-	headers := false
-	for _, opt := range options {
-		if strings.HasPrefix(opt.Key, "headers.") {
-			if !headers {
-				optsBuilder.WriteString("  headers: {\n")
-				headers = true
-			}
-			headerName := strings.TrimPrefix(opt.Key, "headers.")
-			optsBuilder.WriteString(fmt.Sprintf("    '%s': '%s',\n", headerName, escapeJS(opt.Value)))
-		} else {
-			optsBuilder.WriteString(fmt.Sprintf("  %s: '%s',\n", opt.Key, escapeJS(opt.Value)))
-		}
-	}
-
-	if headers {
-		optsBuilder.WriteString("  },\n")
-	}
-
-	optsBuilder.WriteString("}")
-
-	// 構建 fetch 鏈
-	var codeBuilder strings.Builder
-
-	codeBuilder.WriteString(fmt.Sprintf("fetch('%s', %s)\n", url, optsBuilder.String()))
-	codeBuilder.WriteString("  .then(response => {\n")
-	codeBuilder.WriteString("    if (!response.ok) {\n")
-	codeBuilder.WriteString("      throw new Error('Network response was not ok: ' + response.status + ' ' + response.statusText);\n")
-	codeBuilder.WriteString("    }\n")
-
-	// 根據響應類型返回不同格式的數據
-	switch responseType {
-	case TextResponse:
-		codeBuilder.WriteString("    return response.text();\n")
-	case BlobResponse:
-		codeBuilder.WriteString("    return response.blob();\n")
-	default:
-		codeBuilder.WriteString("    return response.json();\n")
-	}
-
-	codeBuilder.WriteString("  })")
-
-	// 添加 then 處理器
-	if then != "" {
-		codeBuilder.WriteString("\n  .then(data => {\n")
-		codeBuilder.WriteString(indent(thenStr, "    "))
-		codeBuilder.WriteString("\n  })")
-	}
-
-	// 添加 catch 處理器
-	if catch != "" {
-		codeBuilder.WriteString("\n  .catch(error => {\n")
-		codeBuilder.WriteString(indent(catchStr, "    "))
-		codeBuilder.WriteString("\n  })")
-	}
-
-	// 添加 finally 處理器
-	if finally != "" {
-		codeBuilder.WriteString("\n  .finally(() => {\n")
-		codeBuilder.WriteString(indent(finallyStr, "    "))
-		codeBuilder.WriteString("\n  })")
-	}
-
-	return JSAction{Code: codeBuilder.String()}
-}
-
 // tryBuilder 用於構建 try-catch-finally 語句的流暢 API
 type tryBuilder struct {
 	tryActions     []JSAction
@@ -608,21 +525,8 @@ func (tb *tryBuilder) build() JSAction {
 
 	var sb strings.Builder
 
-	// try 區塊（最小化）
-	sb.WriteString("try{")
-	for i, action := range tb.tryActions {
-		line := strings.TrimSpace(action.Code)
-		if line != "" {
-			if i > 0 {
-				sb.WriteString(";")
-			}
-			if strings.HasSuffix(line, ";") {
-				line = strings.TrimSuffix(line, ";")
-			}
-			sb.WriteString(line)
-		}
-	}
-	sb.WriteString("}")
+	// try 區塊（最小化）；各區塊中的 Local 名稱在區塊內分配
+	sb.WriteString("try{" + scopedBlock(tb.tryActions) + "}")
 
 	// catch 區塊
 	if len(tb.catchActions) > 0 {
@@ -630,38 +534,12 @@ func (tb *tryBuilder) build() JSAction {
 		if errName == "" {
 			errName = "error" // 預設名稱
 		}
-		sb.WriteString("catch(" + errName + "){")
-		for i, action := range tb.catchActions {
-			line := strings.TrimSpace(action.Code)
-			if line != "" {
-				if i > 0 {
-					sb.WriteString(";")
-				}
-				if strings.HasSuffix(line, ";") {
-					line = strings.TrimSuffix(line, ";")
-				}
-				sb.WriteString(line)
-			}
-		}
-		sb.WriteString("}")
+		sb.WriteString("catch(" + errName + "){" + scopedBlock(tb.catchActions, errName) + "}")
 	}
 
 	// finally 區塊
 	if len(tb.finallyActions) > 0 {
-		sb.WriteString("finally{")
-		for i, action := range tb.finallyActions {
-			line := strings.TrimSpace(action.Code)
-			if line != "" {
-				if i > 0 {
-					sb.WriteString(";")
-				}
-				if strings.HasSuffix(line, ";") {
-					line = strings.TrimSuffix(line, ";")
-				}
-				sb.WriteString(line)
-			}
-		}
-		sb.WriteString("}")
+		sb.WriteString("finally{" + scopedBlock(tb.finallyActions) + "}")
 	}
 
 	return JSAction{Code: sb.String()}
@@ -706,18 +584,20 @@ func Do(params []string, actions ...JSAction) JSAction {
 	}
 	sb.WriteString(")=>{")
 
+	var body strings.Builder
 	for i, action := range actions {
 		line := strings.TrimSpace(action.Code)
 		if line != "" {
 			if i > 0 {
-				sb.WriteString(";")
+				body.WriteString(";")
 			}
 			if strings.HasSuffix(line, ";") {
 				line = strings.TrimSuffix(line, ";")
 			}
-			sb.WriteString(line)
+			body.WriteString(line)
 		}
 	}
+	sb.WriteString(resolveLocals(body.String(), params...))
 
 	sb.WriteString("})(")
 	// 如果有參數，自動傳入 'event'
@@ -769,18 +649,20 @@ func AsyncDo(params []string, actions ...JSAction) JSAction {
 	}
 	sb.WriteString(")=>{")
 
+	var body strings.Builder
 	for i, action := range actions {
 		line := strings.TrimSpace(action.Code)
 		if line != "" {
 			if i > 0 {
-				sb.WriteString(";")
+				body.WriteString(";")
 			}
 			if strings.HasSuffix(line, ";") {
 				line = strings.TrimSuffix(line, ";")
 			}
-			sb.WriteString(line)
+			body.WriteString(line)
 		}
 	}
+	sb.WriteString(resolveLocals(body.String(), params...))
 
 	sb.WriteString("})(")
 	// 如果有參數，自動傳入 'event'
@@ -828,13 +710,14 @@ func StoreResult(varName string, additionalActions ...interface{}) JSAction {
 
 // CreateEl 創建一個 DOM 元素，並返回一個 Elem 物件以及創建元素的 JSAction
 // tagName：要創建的 HTML 元素標籤名
-// varName：可選參數，為創建的元素指定一個變數名稱
+// varName：可選參數，為創建的元素指定一個變數名稱；省略時使用 Local("el_"+tagName)，
+// 名稱由包含宣告的 Fn、Do 等函數或 If、Try 等區塊分配（el_<tag>_N），同一區塊內不重複、重複渲染時輸出相同
 func CreateEl(tagName string, varName ...string) (Elem, JSAction) {
 	var vName string
 	if len(varName) > 0 {
 		vName = varName[0]
 	} else {
-		vName = fmt.Sprintf("el_%s_%d", tagName, generateRandomID())
+		vName = Local("el_" + tagName)
 	}
 
 	jsAction := JSAction{Code: fmt.Sprintf("const %s = document.createElement('%s');", vName, tagName)}
@@ -855,17 +738,20 @@ func (el Elem) Pipe(fn func(Elem, JSAction) []JSAction) []JSAction {
 	return fn(el, dummyAction)
 }
 
-// generateRandomID 生成一個隨機的 ID 用於元素命名
-func generateRandomID() int {
-	return rand.Intn(10000)
-}
-
 // SetTimeout 產生 setTimeout 語法
-func SetTimeout(action JSAction, delayMs int) JSAction {
+// 傳入 handle 時計時器以該名稱記錄，可用 ClearTimer(handle) 取消；同名的計時器會先被清除
+func SetTimeout(action JSAction, delayMs int, handle ...string) JSAction {
+	if len(handle) > 0 {
+		return startTimer("setTimeout", handle[0], delayMs, action)
+	}
 	return JSAction{Code: fmt.Sprintf("setTimeout(\n%s\n, %d)", indent(action.Code, "  "), delayMs)}
 }
 
 // SetInterval 產生 setInterval 語法
-func SetInterval(action JSAction, intervalMs int) JSAction {
+// 傳入 handle 時計時器以該名稱記錄，可用 ClearTimer(handle) 取消；同名的計時器會先被清除
+func SetInterval(action JSAction, intervalMs int, handle ...string) JSAction {
+	if len(handle) > 0 {
+		return startTimer("setInterval", handle[0], intervalMs, action)
+	}
 	return JSAction{Code: fmt.Sprintf("setInterval(\n%s\n, %d)", indent(action.Code, "  "), intervalMs)}
 }
@@ -0,0 +1,172 @@
+// scope.go
+package jsdsl
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	. "github.com/TimLai666/go-vdom/dom"
+)
+
+// Scope 為生成的 JavaScript 分配不重複的變數名稱
+// 同一個 Scope 分配的名稱互不相同，且依呼叫順序決定，輸出可重現
+// 同一頁面的多段腳本可各自使用不同前綴的 Scope，或以 Isolate 包裝，避免頂層的 const/let 互相衝突
+//
+// 用法：
+//
+//	s := js.NewScope("card")
+//	list, create := s.CreateEl("ul") // const card_el_ul_1 = document.createElement('ul');
+//	count := s.Fresh("count")        // card_count_1
+type Scope struct {
+	prefix string
+	mu     sync.Mutex
+	counts map[string]int
+	taken  map[string]bool
+}
+
+// NewScope 創建名稱分配器；prefix 會加在每個名稱之前（可省略）
+func NewScope(prefix ...string) *Scope {
+	s := &Scope{counts: map[string]int{}, taken: map[string]bool{}}
+	if len(prefix) > 0 && prefix[0] != "" {
+		s.prefix = sanitizeIdent(prefix[0]) + "_"
+	}
+	return s
+}
+
+// Reserve 標記已被使用的名稱（例如手寫代碼中的變數），Fresh 不會再返回它們
+func (s *Scope) Reserve(names ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, n := range names {
+		s.taken[n] = true
+	}
+}
+
+// Fresh 返回以 name 為基礎的新識別字，格式為 [prefix_]name_N
+// name 中不能用於識別字的字元會被替換為底線；編號後綴確保結果不會是保留字
+func (s *Scope) Fresh(name string) string {
+	base := s.prefix + sanitizeIdent(name) + "_"
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for {
+		s.counts[base]++
+		id := base + strconv.Itoa(s.counts[base])
+		if !s.taken[id] {
+			s.taken[id] = true
+			return id
+		}
+	}
+}
+
+// Const 以新名稱宣告常數，返回該名稱的表達式與宣告動作
+func (s *Scope) Const(name string, value any) (Expr, JSAction) {
+	id := s.Fresh(name)
+	return Ident(id), Const(id, value)
+}
+
+// Let 以新名稱宣告變數，返回該名稱的表達式與宣告動作
+func (s *Scope) Let(name string, value any) (Expr, JSAction) {
+	id := s.Fresh(name)
+	return Ident(id), Let(id, value)
+}
+
+// CreateEl 創建 DOM 元素並以新名稱保存，返回 Elem 與創建元素的動作
+func (s *Scope) CreateEl(tagName string) (Elem, JSAction) {
+	return CreateEl(tagName, s.Fresh("el_"+tagName))
+}
+
+// sanitizeIdent 將字串轉為合法的識別字片段
+func sanitizeIdent(name string) string {
+	var sb strings.Builder
+	for i, r := range name {
+		switch {
+		case r == '_' || r == '$' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z':
+			sb.WriteRune(r)
+		case r >= '0' && r <= '9':
+			if i == 0 {
+				sb.WriteByte('_')
+			}
+			sb.WriteRune(r)
+		default:
+			sb.WriteByte('_')
+		}
+	}
+	if sb.Len() == 0 {
+		return "v"
+	}
+	return sb.String()
+}
+
+// localSeq 為 Local 產生暫時名稱的編號，只用於區分不同的呼叫，不會出現在函數的輸出中
+var localSeq atomic.Uint64
+
+var (
+	// localDeclPattern 匹配以 const/let/var 宣告的 Local 暫時名稱 name$gvdN$
+	localDeclPattern = regexp.MustCompile(`\b(?:const|let|var)\s+(([A-Za-z_$][A-Za-z0-9_$]*?)\$gvd\d+\$)`)
+	// blockDeclPattern 只匹配區塊作用域的 const/let 宣告；var 提升到所在函數，由函數分配
+	blockDeclPattern  = regexp.MustCompile(`\b(?:const|let)\s+(([A-Za-z_$][A-Za-z0-9_$]*?)\$gvd\d+\$)`)
+	identTokenPattern = regexp.MustCompile(`[A-Za-z_$][A-Za-z0-9_$]*`)
+)
+
+// Local 返回區域變數的暫時名稱，用於 Let、Const、CreateEl 等宣告
+// 實際名稱（name_N）由宣告所在的函數或區塊在輸出時分配：Fn、AsyncFn、Do、AsyncDo、Handler、Isolate，
+// 以及 If、Switch、While、ForOf、ForEachJS、Try、Debounce 等控制結構的區塊；
+// 同一區塊內不重複、不遮蔽其中已有的名稱，且只取決於區塊本身的代碼，重複渲染時輸出相同
+// 不在任何函數或區塊中的頂層宣告請以 Isolate 包裝
+//
+// 用法：
+//
+//	total := js.Local("total")
+//	js.Fn(nil, js.Let(total, "0"), js.Log(total)) // ()=>{let total_1=0;console.log(total_1)}
+func Local(name string) string {
+	return sanitizeIdent(name) + "$gvd" + strconv.FormatUint(localSeq.Add(1), 10) + "$"
+}
+
+// resolveLocals 為函數主體中宣告的 Local 名稱分配實際名稱
+// 巢狀函數已先分配自己的名稱；此處只處理本層宣告的名稱，並避開主體中已出現的所有識別字與 params
+func resolveLocals(body string, params ...string) string {
+	return resolveDecls(localDeclPattern, body, params)
+}
+
+// scopedBlock 將動作合併為 {} 區塊的內容，並分配區塊中以 const/let 宣告的 Local 名稱
+// params 為區塊中已綁定的名稱，例如 catch 的錯誤變數與 for...of 的項目變數
+func scopedBlock(actions []JSAction, params ...string) string {
+	return resolveDecls(blockDeclPattern, block(actions), params)
+}
+
+// resolveDecls 為 body 中以 pattern 匹配的宣告分配實際名稱
+func resolveDecls(pattern *regexp.Regexp, body string, params []string) string {
+	if !strings.Contains(body, "$gvd") {
+		return body
+	}
+	var pairs []string
+	var s *Scope
+	seen := make(map[string]bool)
+	for _, m := range pattern.FindAllStringSubmatch(body, -1) {
+		if seen[m[1]] {
+			continue
+		}
+		seen[m[1]] = true
+		if s == nil {
+			s = NewScope()
+			s.Reserve(params...)
+			s.Reserve(identTokenPattern.FindAllString(body, -1)...)
+		}
+		pairs = append(pairs, m[1], s.Fresh(m[2]))
+	}
+	if len(pairs) == 0 {
+		return body
+	}
+	return strings.NewReplacer(pairs...).Replace(body)
+}
+
+// Isolate 將頂層腳本包在區塊 {...} 中：其中的 const/let 只在區塊內有效，
+// 同一頁面多個組件的 <script> 即使宣告同名變數也不會衝突；Local 名稱在區塊內分配
+//
+// 用法：Script(nil, js.Isolate(js.Const("items", js.Value(items)), js.Call("render", "items")).Code)
+func Isolate(actions ...JSAction) JSAction {
+	return JSAction{Code: "{" + resolveLocals(block(actions)) + "}"}
+}
@@ -0,0 +1,128 @@
+// scope_test.go
+package jsdsl
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/TimLai666/go-vdom/dom"
+)
+
+func TestScopeFresh(t *testing.T) {
+	s := NewScope("card")
+	if got := []string{s.Fresh("count"), s.Fresh("count"), s.Fresh("1x")}; got[0] != "card_count_1" || got[1] != "card_count_2" || got[2] != "card__1x_1" {
+		t.Errorf("Fresh = %v", got)
+	}
+	s.Reserve("card_n_1")
+	if got := s.Fresh("n"); got != "card_n_2" {
+		t.Errorf("Fresh should skip reserved names, got %s", got)
+	}
+}
+
+// renderList 模擬每次請求重新建立頁面腳本
+func renderList() string {
+	list, createList := CreateEl("ul")
+	item, createItem := CreateEl("li")
+	other, createOther := CreateEl("li")
+	total := Local("total")
+	return Fn(nil,
+		createList, createItem, createOther,
+		Let(total, "0"),
+		list.AppendChild(item), list.AppendChild(other),
+		El("#b").On("click", func(ev Event) JSAction {
+			inner, create := CreateEl("li")
+			return Do(nil, create, list.AppendChild(inner), Log(total))
+		}),
+	).Code
+}
+
+func TestCreateElNamesAreStableAcrossRenders(t *testing.T) {
+	want := "()=>{const el_ul_1 = document.createElement('ul');const el_li_2 = document.createElement('li');const el_li_3 = document.createElement('li');" +
+		"let total_1=0;el_ul_1.appendChild(el_li_2);el_ul_1.appendChild(el_li_3);" +
+		"document.querySelector('#b').addEventListener('click',function(event){(()=>{const el_li_1 = document.createElement('li');el_ul_1.appendChild(el_li_1);console.log(total_1)})()})}"
+	for i := 0; i < 3; i++ {
+		if got := renderList(); got != want {
+			t.Fatalf("render %d:\ngot  %s\nwant %s", i+1, got, want)
+		}
+	}
+}
+
+func TestLocalAvoidsParamsAndExistingNames(t *testing.T) {
+	_, create := CreateEl("div")
+	got := Fn([]string{"el_div_1"}, create, Log("el_div_2")).Code
+	want := "(el_div_1)=>{const el_div_3 = document.createElement('div');console.log(el_div_2)}"
+	if got != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+}
+
+func TestIsolate(t *testing.T) {
+	got := Isolate(Const(Local("x"), 1), Const("y", 2)).Code
+	if want := "{const x_1=1;const y=2}"; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func TestBuilderScope(t *testing.T) {
+	build := func() string {
+		b := NewJSActionBuilder()
+		a := b.CreateElement("div")
+		c := b.CreateElement("div")
+		b.AppendChild(a, c)
+		return b.Build().Code
+	}
+	first := build()
+	if first != build() {
+		t.Errorf("builder output should be stable across builds")
+	}
+	want := "const el_div_1 = document.createElement('div');const el_div_2 = document.createElement('div');el_div_1.appendChild(el_div_2)"
+	if !strings.Contains(first, want) {
+		t.Errorf("got %s, want substring %s", first, want)
+	}
+}
+
+func TestLocalsResolvedInBlocks(t *testing.T) {
+	// 控制結構的區塊即使直接作為頂層腳本，也不會留下 name$gvdN$ 暫時名稱
+	render := func() map[string]string {
+		_, createIf := CreateEl("div")
+		_, createElse := CreateEl("div")
+		_, createEach := CreateEl("li")
+		_, createTry := CreateEl("p")
+		return map[string]string{
+			"if":      If("x", createIf).Else(createElse).Code,
+			"forEach": ForEachJS("items", "item", createEach).Code,
+			"try":     Try(createTry).Catch("e", Log("e")).End().Code,
+			"forOf":   ForOf("items", "el_li_1", Const(Local("el_li"), "el_li_1")).Code,
+		}
+	}
+	want := map[string]string{
+		"if":      "if(x){const el_div_1 = document.createElement('div')}else{const el_div_1 = document.createElement('div')}",
+		"forEach": "function(item) {\n  const el_li_1 = document.createElement('li');\n",
+		"try":     "try{const el_p_1 = document.createElement('p')}catch(e){console.log(e)}",
+		"forOf":   "for(const el_li_1 of items){const el_li_2=el_li_1}",
+	}
+	for i := 0; i < 2; i++ {
+		for name, got := range render() {
+			if strings.Contains(got, "$gvd") || !strings.Contains(got, want[name]) {
+				t.Errorf("%s (render %d):\ngot  %s\nwant %s", name, i+1, got, want[name])
+			}
+		}
+	}
+}
+
+func TestBlockLocalsInsideFunction(t *testing.T) {
+	outer, createOuter := CreateEl("div")
+	inner, createInner := CreateEl("div")
+	got := Fn(nil, createOuter, If("x", createInner, outer.AppendChild(inner)).End()).Code
+	want := "()=>{const el_div_2 = document.createElement('div');if(x){const el_div_1 = document.createElement('div');el_div_2.appendChild(el_div_1)}}"
+	if got != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+
+	// var 提升到函數，由函數分配，區塊外也能引用
+	n := Local("n")
+	got = Fn(nil, If("x", JSAction{Code: "var " + n + "=1"}).End(), Log(n)).Code
+	if want := "()=>{if(x){var n_1=1};console.log(n_1)}"; got != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+}
@@ -0,0 +1,41 @@
+// signal.go
+package jsdsl
+
+import (
+	. "github.com/TimLai666/go-vdom/dom"
+)
+
+// gvdRuntime 是客戶端運行時的全域物件
+var gvdRuntime = Ident("window").Dot("__gvd")
+
+// Signal 在客戶端運行時宣告狀態；initial 為 Go 值（以 Value 轉為字面量）或 Expr
+// 已宣告的 signal 保留現有的值，腳本重複執行不會重設狀態
+// 元素以 data-gvd-text、data-gvd-attr、data-gvd-class、data-gvd-show 屬性綁定到 signal
+//
+// 用法：
+//
+//	Span(Props{"data-gvd-text": "count"}, "0")
+//	Button(Props{"data-gvd-show": "!done", "onClick": js.Set("count", js.Get("count").Add(js.Num(1)))}, "+1")
+//	Script(nil, js.Signal("count", 0).Code)
+func Signal(name string, initial any) JSAction {
+	return gvdRuntime.Method("signal", Str(name), Value(initial)).Action()
+}
+
+// Get 返回 signal 目前的值
+func Get(name string) Expr {
+	return gvdRuntime.Method("get", Str(name))
+}
+
+// Set 更新 signal 並同步所有綁定的元素；value 為 JavaScript 代碼字串、JSAction 或 Expr（Go 值請使用 Value）
+//
+// 用法：js.Set("open", js.Not(js.Get("open")))
+func Set(name string, value any) JSAction {
+	return gvdRuntime.Method("set", Str(name), toExpr(value)).Action()
+}
+
+// Watch 在 signal 變化時執行動作；動作中可使用 value（新值）與 prev（舊值）
+//
+// 用法：js.Watch("count", js.Log("'count: ' + value"))
+func Watch(name string, actions ...JSAction) JSAction {
+	return gvdRuntime.Method("watch", Str(name), Raw("function(value,prev){"+resolveLocals(block(actions), "value", "prev")+"}")).Action()
+}
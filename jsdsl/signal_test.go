@@ -0,0 +1,34 @@
+// signal_test.go
+package jsdsl
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSignalQuotesNamesAndValues(t *testing.T) {
+	// signal 名稱與初始值都經過轉義，不會提前結束 <script>
+	code := Signal("a'</script>", map[string]any{"name": "</script>"}).Code
+	if strings.Contains(code, "</script>") {
+		t.Errorf("signal output must not contain </script>: %s", code)
+	}
+	checkFragments(t, "signal", code, `'a\'\u003c/script\u003e'`, `{"name":"\u003c/script\u003e"}`)
+
+	// Expr 與代碼字串原樣作為值，不再轉為字面量
+	checkFragments(t, "signal from expr", Signal("n", Get("m")).Code, `signal('n', window.__gvd.get('m'))`)
+	checkFragments(t, "set expr", Set("count", Get("count").Add(Num(1))).Code, `set('count', window.__gvd.get('count') + 1)`)
+	checkFragments(t, "set code", Set("open", "!x").Code, `set('open', !x)`)
+	checkFragments(t, "set value", Set("s", Value("x'")).Code, `set('s', "x\u0027")`)
+}
+
+func TestWatch(t *testing.T) {
+	code := Watch("count", Log("'count: ' + value")).Code
+	checkFragments(t, "watch", code, `watch('count', function(value,prev){`, `console.log('count: ' + value)`)
+
+	// Local 名稱在處理函數內分配，不會遮蔽 value 與 prev
+	code = Watch("count", Const(Local("value"), "value-prev"), Const(Local("prev"), 1)).Code
+	checkFragments(t, "watch locals", code, "const value_1=value-prev", "const prev_1=1")
+	if strings.Contains(code, "$gvd") {
+		t.Errorf("placeholder left in watch handler: %s", code)
+	}
+}
@@ -0,0 +1,197 @@
+// template.go
+package jsdsl
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	. "github.com/TimLai666/go-vdom/dom"
+)
+
+// templateFieldPattern 匹配模板中的 {{field}} 佔位符
+var templateFieldPattern = regexp.MustCompile(`\{\{\s*([^{}]+?)\s*\}\}`)
+
+const svgNamespace = "http://www.w3.org/2000/svg"
+
+// RenderTemplate 將 VNode 模板編譯為在客戶端建立 DOM 的函數，並以 dataExpr 呼叫，返回建立的元素
+// 模板中的 {{field}}（可用 {{user.name}} 存取巢狀欄位，{{.}} 為資料本身）取自資料物件：
+// 文字以 textContent / 文字節點寫入，屬性以 setAttribute 設定，資料不會被解析為 HTML
+// 屬性值只有單一佔位符時，null、undefined 與 false 會省略該屬性，true 輸出為布林屬性
+// href、src 等 URL 屬性含佔位符時，javascript:、vbscript: 與 data: 網址會被替換為 about:invalid
+// 事件處理器（on*）原樣設定，不進行佔位符替換；onDOMReady 與 gvd: 前綴的保留屬性被忽略
+//
+// 列表搭配 ForEachJS 渲染：
+//
+//	row := Li(Props{"class": "list-group-item", "data-id": "{{id}}"},
+//	    Element("strong", nil, "{{name}}"), ": {{message}}",
+//	)
+//	js.ForEachJS("items", "item",
+//	    js.CallMethod("list", "appendChild", js.RenderTemplate(row, js.Ident("item"))),
+//	)
+func RenderTemplate(tpl VNode, dataExpr Expr) Expr {
+	c := &templateCompiler{scope: NewScope()}
+	root := c.node(tpl, "", false)
+	body := "function s(v){return v==null?'':String(v)}"
+	if c.urls {
+		body += urlFilter
+	}
+	body += c.sb.String() + "return " + root
+	return Expr{literal{code: "(function(d){" + body + "})", p: precPrimary}}.Call(dataExpr)
+}
+
+// templateCompiler 逐節點輸出建立 DOM 的語句
+type templateCompiler struct {
+	scope *Scope
+	sb    strings.Builder
+	urls  bool // 是否使用了 urlFilter
+}
+
+// urlAttrs 是值為網址的屬性，以資料填入時需過濾可執行的網址
+var urlAttrs = map[string]bool{
+	"href": true, "src": true, "action": true, "formaction": true, "xlink:href": true,
+	"poster": true, "cite": true, "data": true, "background": true,
+}
+
+// urlFilter 定義 u(v)：移除空白與控制字元後以 javascript:、vbscript: 或 data: 開頭的網址替換為 about:invalid
+// 瀏覽器解析網址時會忽略這些字元，因此 " java\tscript:" 也會被過濾
+const urlFilter = `function u(v){return /^(?:javascript|vbscript|data):/i.test(v.replace(/[\u0000-\u0020]/g,''))?'about:invalid#gvd':v}`
+
+func (c *templateCompiler) stmt(format string, args ...any) {
+	c.sb.WriteString(fmt.Sprintf(format, args...))
+	c.sb.WriteString(";")
+}
+
+// node 輸出建立 v 的語句並返回保存該節點的變數；parent 非空時附加到 parent
+func (c *templateCompiler) node(v VNode, parent string, inSVG bool) string {
+	if v.Tag == "" {
+		id := c.scope.Fresh("t")
+		c.stmt("var %s=document.createTextNode(%s)", id, textExpr(v.Content))
+		if parent != "" {
+			c.stmt("%s.appendChild(%s)", parent, id)
+		}
+		return id
+	}
+
+	inSVG = inSVG || v.Tag == "svg"
+	id := c.scope.Fresh("n")
+	if inSVG {
+		c.stmt("var %s=document.createElementNS(%s,%s)", id, Str(svgNamespace), Str(v.Tag))
+	} else {
+		c.stmt("var %s=document.createElement(%s)", id, Str(v.Tag))
+	}
+	for _, k := range sortedPropKeys(v.Props) {
+		c.attr(id, k, v.Props[k])
+	}
+
+	switch {
+	case v.Content != "":
+		c.stmt("%s.textContent=%s", id, textExpr(v.Content))
+	case len(v.Children) == 1 && v.Children[0].Tag == "":
+		c.stmt("%s.textContent=%s", id, textExpr(v.Children[0].Content))
+	default:
+		for _, child := range v.Children {
+			c.node(child, id, inSVG)
+		}
+	}
+	if parent != "" {
+		c.stmt("%s.appendChild(%s)", parent, id)
+	}
+	return id
+}
+
+// attr 輸出設定屬性的語句，規則與伺服器端渲染一致
+func (c *templateCompiler) attr(id, k string, val any) {
+	if k == "onDOMReady" || strings.HasPrefix(k, "gvd:") {
+		return
+	}
+	var s string
+	switch t := val.(type) {
+	case bool:
+		if t {
+			c.stmt("%s.setAttribute(%s,'')", id, Str(k))
+		}
+		return
+	case JSAction:
+		s = t.Code
+	case string:
+		s = t
+	default:
+		s = fmt.Sprint(t)
+	}
+	if s == "false" {
+		return
+	}
+	if len(k) > 2 && strings.HasPrefix(k, "on") {
+		c.stmt("%s.setAttribute(%s,%s)", id, Str(k), Str(s))
+		return
+	}
+	isURL := urlAttrs[strings.ToLower(k)] && templateFieldPattern.MatchString(s)
+	c.urls = c.urls || isURL
+	if m := templateFieldPattern.FindStringSubmatch(s); m != nil && m[0] == s {
+		// 單一佔位符：依值的型別決定是否輸出屬性
+		value := "String(v)"
+		if isURL {
+			value = "u(String(v))"
+		}
+		c.stmt("(function(v){if(v!=null&&v!==false)%s.setAttribute(%s,v===true?'':%s)})(%s)", id, Str(k), value, fieldExpr(m[1]))
+		return
+	}
+	if isURL {
+		c.stmt("%s.setAttribute(%s,u(%s))", id, Str(k), textExpr(s))
+		return
+	}
+	c.stmt("%s.setAttribute(%s,%s)", id, Str(k), textExpr(s))
+}
+
+// textExpr 將含佔位符的文字轉為字串串接表達式
+func textExpr(text string) string {
+	var parts []string
+	last := 0
+	for _, loc := range templateFieldPattern.FindAllStringSubmatchIndex(text, -1) {
+		if loc[0] > last {
+			parts = append(parts, Str(text[last:loc[0]]).String())
+		}
+		parts = append(parts, "s("+fieldExpr(text[loc[2]:loc[3]])+")")
+		last = loc[1]
+	}
+	if last < len(text) || len(parts) == 0 {
+		parts = append(parts, Str(text[last:]).String())
+	}
+	return strings.Join(parts, "+")
+}
+
+// fieldExpr 將欄位路徑轉為資料物件的存取表達式；{{.}} 為資料本身（如字串陣列的項目）
+// 巢狀欄位以 ?. 存取，中間值為空時不會拋出錯誤
+func fieldExpr(path string) string {
+	if path == "." {
+		return "d"
+	}
+	var sb strings.Builder
+	sb.WriteString("d")
+	for i, part := range strings.Split(path, ".") {
+		part = strings.TrimSpace(part)
+		if i > 0 {
+			sb.WriteString("?")
+		}
+		if identPattern.MatchString(part) {
+			sb.WriteString("." + part)
+		} else {
+			if i > 0 {
+				sb.WriteString(".")
+			}
+			sb.WriteString("[" + Str(part).String() + "]")
+		}
+	}
+	return sb.String()
+}
+
+func sortedPropKeys(p Props) []string {
+	keys := make([]string, 0, len(p))
+	for k := range p {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,95 @@
+// template_test.go
+package jsdsl
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/TimLai666/go-vdom/dom"
+)
+
+func TestRenderTemplate(t *testing.T) {
+	row := VNode{
+		Tag: "li",
+		Props: Props{
+			"class":      "item",
+			"data-id":    "{{id}}",
+			"checked":    true,
+			"onClick":    "go('{{id}}')",
+			"onDOMReady": "x()",
+			"gvd:spec":   "y",
+		},
+		Children: []VNode{
+			{Tag: "strong", Children: []VNode{{Content: "{{user.name}}"}}},
+			{Content: ": {{message}}!"},
+		},
+	}
+	code := RenderTemplate(row, Ident("item")).String()
+	if !strings.HasSuffix(code, "return n_1})(item)") {
+		t.Errorf("template should be called with the data expression: %s", code)
+	}
+	checkFragments(t, "attributes", code,
+		`n_1.setAttribute('class','item')`,
+		`n_1.setAttribute('checked','')`,
+		// 單一佔位符：null、undefined 與 false 省略屬性
+		`(function(v){if(v!=null&&v!==false)n_1.setAttribute('data-id',v===true?'':String(v))})(d.id)`,
+		// 事件處理器原樣設定，不替換佔位符
+		`n_1.setAttribute('onClick','go(\'{{id}}\')')`,
+	)
+	if strings.Contains(code, "x()") || strings.Contains(code, "gvd:") {
+		t.Errorf("onDOMReady and reserved props should be ignored: %s", code)
+	}
+
+	// 文字以 textContent 與文字節點寫入，巢狀欄位以 ?. 存取
+	checkFragments(t, "text", code,
+		`n_2.textContent=s(d.user?.name)`,
+		`document.createTextNode(': '+s(d.message)+'!')`,
+	)
+	if strings.Contains(code, "innerHTML") {
+		t.Errorf("data must not be parsed as HTML: %s", code)
+	}
+}
+
+func TestRenderTemplateFields(t *testing.T) {
+	// {{.}} 為資料本身
+	checkFragments(t, "dot", RenderTemplate(VNode{Tag: "span", Content: "{{.}}"}, Ident("s")).String(), `n_1.textContent=s(d)`)
+
+	// 非識別字欄位以 [] 存取，文字中的 < > 被轉義
+	quoted := RenderTemplate(VNode{Tag: "b", Content: "<{{a-b.1}}>"}, Ident("d")).String()
+	checkFragments(t, "quoted fields", quoted, `'\u003c'+s(d['a-b']?.['1'])+'\u003e'`)
+
+	// svg 與其中的元素以 createElementNS 建立
+	svg := VNode{Tag: "svg", Children: []VNode{{Tag: "circle", Props: Props{"r": "{{ r }}"}}}}
+	code := RenderTemplate(svg, Ident("d")).String()
+	checkFragments(t, "svg", code,
+		`createElementNS('http://www.w3.org/2000/svg','svg')`,
+		`createElementNS('http://www.w3.org/2000/svg','circle')`,
+		`n_2.setAttribute('r',v===true?'':String(v))})(d.r)`,
+	)
+}
+
+func TestRenderTemplateFiltersURLs(t *testing.T) {
+	link := VNode{Tag: "a", Props: Props{"href": "{{url}}", "src": "/img/{{url}}", "title": "{{url}}"}}
+	code := RenderTemplate(link, Ident("item")).String()
+
+	// 單一佔位符與串接的網址屬性都經過 u 過濾，其他屬性不受影響
+	checkFragments(t, "url attributes", code,
+		`n_1.setAttribute('href',v===true?'':u(String(v)))`,
+		`n_1.setAttribute('src',u('/img/'+s(d.url)))`,
+		`n_1.setAttribute('title',v===true?'':String(v))`,
+	)
+	// 沒有以資料填入的網址時不輸出過濾函數
+	if static := RenderTemplate(VNode{Tag: "a", Props: Props{"href": "/home", "title": "{{t}}"}}, Ident("d")).String(); strings.Contains(static, "function u(") {
+		t.Errorf("static URLs need no filter: %s", static)
+	}
+
+	script := `var document={createElement(){return {a:{},setAttribute(k,v){this.a[k]=v}}}};var out=[];
+for (const url of ['javascript:alert(1)',' JaVa\tScript:x','data:text/html,x','vbscript:x','https://ok','/rel']) {
+  var item={url:url};out.push(` + code + `.a.href)
+}
+console.log(JSON.stringify(out))`
+	want := `["about:invalid#gvd","about:invalid#gvd","about:invalid#gvd","about:invalid#gvd","https://ok","/rel"]`
+	if got := runJS(t, script); got != want {
+		t.Errorf("href values:\ngot  %s\nwant %s", got, want)
+	}
+}
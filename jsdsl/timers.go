@@ -0,0 +1,67 @@
+// timers.go
+package jsdsl
+
+import (
+	"fmt"
+	"hash/fnv"
+
+	. "github.com/TimLai666/go-vdom/dom"
+)
+
+// timerRegistry 取得（必要時建立）與客戶端運行時共用的 window.__gvd；具名計時器存放在其 timers 中
+const timerRegistry = "(window.__gvd||(window.__gvd={}))"
+
+// startTimer 以 handle 命名啟動計時器；同名的計時器會先被清除，重複執行不會累積
+func startTimer(fn, handle string, delayMs int, action JSAction) JSAction {
+	return JSAction{Code: fmt.Sprintf("(function(g,k){var m=g.timers||(g.timers={});clearTimeout(m[k]);m[k]=%s(%s,%d)})(%s,%s)",
+		fn, action.Code, delayMs, timerRegistry, Str(handle))}
+}
+
+// ClearTimer 取消以 SetTimeout 或 SetInterval 的 handle 命名的計時器
+//
+// 用法：
+//
+//	js.SetInterval(js.Fn(nil, js.Call("poll")), 5000, "poll")
+//	js.ClearTimer("poll")
+func ClearTimer(handle string) JSAction {
+	// clearTimeout 與 clearInterval 共用同一組計時器 ID，可互換使用
+	return JSAction{Code: fmt.Sprintf("(function(g){var m=g&&g.timers;if(m){clearTimeout(m[%[1]s]);delete m[%[1]s]}})(window.__gvd)", Str(handle))}
+}
+
+// timerKey 由代碼與間隔產生穩定的鍵，同一處的 Debounce/Throttle 在每次執行時都對應同一個計時器
+func timerKey(kind string, ms int, actions []JSAction) string {
+	h := fnv.New32a()
+	fmt.Fprintf(h, "%s|%d|%s", kind, ms, resolveLocals(block(actions)))
+	return fmt.Sprintf("%s:%08x", kind, h.Sum32())
+}
+
+// timerScope 是 Debounce/Throttle 存放計時器的物件：在事件處理器中為觸發的元素，否則為 window.__gvd
+const timerScope = "this&&this.nodeType?this:" + timerRegistry
+
+// Debounce 延遲執行動作，在 ms 毫秒內再次觸發時重新計時，只執行最後一次
+// 適合用於內聯事件處理器或 On 綁定的處理器；計時器存放在觸發的元素上，不宣告任何變數，重複執行不會衝突
+// 動作內可以使用 this 與 event
+//
+// 用法：
+//
+//	TextField(Props{
+//	    "label":   "搜尋",
+//	    "onInput": js.Debounce(300, js.Call("search", "event.target.value")),
+//	})
+func Debounce(ms int, actions ...JSAction) JSAction {
+	return JSAction{Code: fmt.Sprintf("(function(s,k,f){var m=s.__gvdTimers||(s.__gvdTimers={});clearTimeout(m[k]);m[k]=setTimeout(f,%d)})(%s,%s,()=>{%s})",
+		ms, timerScope, Str(timerKey("debounce", ms, actions)), resolveLocals(block(actions)))}
+}
+
+// Throttle 立即執行動作，之後 ms 毫秒內的觸發都被忽略
+// 與 Debounce 相同，計時器存放在觸發的元素上，重複執行不會衝突
+//
+// 用法：
+//
+//	js.El("#list").On("scroll", func(ev js.Event) JSAction {
+//	    return js.Throttle(100, js.Call("loadMore"))
+//	}, js.EventOptions{Passive: true})
+func Throttle(ms int, actions ...JSAction) JSAction {
+	return JSAction{Code: fmt.Sprintf("(function(s,k,f){var m=s.__gvdTimers||(s.__gvdTimers={});if(m[k])return;m[k]=setTimeout(function(){delete m[k]},%d);f()})(%s,%s,()=>{%s})",
+		ms, timerScope, Str(timerKey("throttle", ms, actions)), resolveLocals(block(actions)))}
+}
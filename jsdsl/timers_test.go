@@ -0,0 +1,86 @@
+// timers_test.go
+package jsdsl
+
+import (
+	"testing"
+
+	. "github.com/TimLai666/go-vdom/dom"
+)
+
+func TestDebounceThrottle(t *testing.T) {
+	tests := []struct {
+		name   string
+		action JSAction
+		want   string
+	}{
+		{
+			"debounce",
+			Debounce(300, Call("search", "event.target.value")),
+			"(function(s,k,f){var m=s.__gvdTimers||(s.__gvdTimers={});clearTimeout(m[k]);m[k]=setTimeout(f,300)})" +
+				"(this&&this.nodeType?this:(window.__gvd||(window.__gvd={})),'debounce:4e0d1b66',()=>{search(event.target.value)})",
+		},
+		{
+			"throttle",
+			Throttle(100, Call("loadMore")),
+			"(function(s,k,f){var m=s.__gvdTimers||(s.__gvdTimers={});if(m[k])return;m[k]=setTimeout(function(){delete m[k]},100);f()})" +
+				"(this&&this.nodeType?this:(window.__gvd||(window.__gvd={})),'throttle:118e8654',()=>{loadMore()})",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.action.Code != tt.want {
+				t.Errorf("got  %s\nwant %s", tt.action.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestTimerKeys(t *testing.T) {
+	search := []JSAction{Call("search", "q")}
+	key := timerKey("debounce", 300, search)
+	if again := timerKey("debounce", 300, []JSAction{Call("search", "q")}); again != key {
+		t.Errorf("same code should reuse the timer key: %s != %s", again, key)
+	}
+	for name, other := range map[string]string{
+		"delay":   timerKey("debounce", 200, search),
+		"actions": timerKey("debounce", 300, []JSAction{Call("search", "r")}),
+		"kind":    timerKey("throttle", 300, search),
+	} {
+		if other == key {
+			t.Errorf("different %s should use a different key, both %s", name, key)
+		}
+	}
+}
+
+func TestNamedTimers(t *testing.T) {
+	poll := Fn(nil, Call("poll"))
+	tests := []struct {
+		name   string
+		action JSAction
+		want   string
+	}{
+		{
+			"named timeout",
+			SetTimeout(poll, 500, "poll"),
+			"(function(g,k){var m=g.timers||(g.timers={});clearTimeout(m[k]);m[k]=setTimeout(()=>{poll()},500)})((window.__gvd||(window.__gvd={})),'poll')",
+		},
+		{
+			"named interval",
+			SetInterval(poll, 5000, "poll"),
+			"(function(g,k){var m=g.timers||(g.timers={});clearTimeout(m[k]);m[k]=setInterval(()=>{poll()},5000)})((window.__gvd||(window.__gvd={})),'poll')",
+		},
+		{
+			"clear",
+			ClearTimer("poll"),
+			"(function(g){var m=g&&g.timers;if(m){clearTimeout(m['poll']);delete m['poll']}})(window.__gvd)",
+		},
+		{"unnamed timeout", SetTimeout(poll, 500), "setTimeout(\n  ()=>{poll()}\n, 500)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.action.Code != tt.want {
+				t.Errorf("got  %s\nwant %s", tt.action.Code, tt.want)
+			}
+		})
+	}
+}
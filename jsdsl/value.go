@@ -0,0 +1,32 @@
+// value.go
+package jsdsl
+
+import (
+	"strings"
+
+	"github.com/TimLai666/go-vdom/internal/jsvalue"
+)
+
+// Value 將 Go 值轉為 JavaScript 字面量表達式，可安全地放在 <script> 與內聯事件處理器中
+// 字串中的 </script>、<!--、引號與 U+2028/U+2029 都會被轉義；time.Time 成為 Date、[]byte 成為 Uint8Array，
+// map 依鍵排序輸出（非字串的鍵轉為字串），json.Marshaler 與 struct 遵循 encoding/json 的規則
+//
+// 用法：
+//
+//	js.Const("user", js.Value(map[string]any{"name": name, "joined": joinedAt}))
+//	// const user={"joined":new Date("2024-01-02T03:04:05Z"),"name":"\u003c/script\u003e"}
+func Value(v any) Expr {
+	if e, ok := v.(Expr); ok {
+		return e
+	}
+	code := jsvalue.Encode(v)
+	p := precPrimary
+	switch {
+	case strings.HasPrefix(code, "new "):
+		p = precCall
+	case code != "" && (code[0] == '-' || code[0] >= '0' && code[0] <= '9'):
+		// 數字作為成員存取對象時需要括號，負數作為一元運算元時需要空格
+		p = precNew
+	}
+	return Expr{literal{code: code, p: p}}
+}
@@ -5,10 +5,12 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"strings"
 
 	comp "github.com/TimLai666/go-vdom/components"
 	control "github.com/TimLai666/go-vdom/control"
 	. "github.com/TimLai666/go-vdom/dom"
+	"github.com/TimLai666/go-vdom/gvd"
 	js "github.com/TimLai666/go-vdom/jsdsl"
 )
 
@@ -40,6 +42,24 @@ func main() {
 		// 設置內容類型為JSON
 		w.Header().Set("Content-Type", "application/json")
 
+		// POST 時驗證表單，缺少欄位以 422 返回各欄位的錯誤訊息
+		if r.Method == http.MethodPost {
+			var input ApiData
+			_ = json.NewDecoder(r.Body).Decode(&input)
+			errs := map[string]string{}
+			if strings.TrimSpace(input.Name) == "" {
+				errs["name"] = "請輸入姓名"
+			}
+			if strings.TrimSpace(input.Message) == "" {
+				errs["message"] = "請輸入訊息"
+			}
+			if len(errs) > 0 {
+				w.WriteHeader(http.StatusUnprocessableEntity)
+				_ = json.NewEncoder(w).Encode(map[string]any{"errors": errs})
+				return
+			}
+		}
+
 		// 創建一些測試數據
 		data := []ApiData{
 			{Id: 1, Name: "項目一", Message: "這是從API獲取的第一條消息"},
@@ -52,10 +72,8 @@ func main() {
 	})
 
 	// 處理HTTP請求的函數
-	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
-		// 設置內容類型為HTML
-		w.Header().Set("Content-Type", "text/html; charset=utf-8")
-
+	// gvd.Handler 負責設置 Content-Type、ETag、HEAD 與 JSON 協商，並渲染返回的 VNode
+	http.Handle("/", gvd.Handler(func(r *http.Request) (VNode, error) {
 		// 使用 Document 函數創建一個完整的 HTML 文檔
 		doc := Document(
 			"我的網頁", // 頁面標題
@@ -176,31 +194,28 @@ func main() {
 						"onClick": js.AsyncDo(nil,
 							js.Log("'開始獲取數據...'"),
 							js.Try(
-								js.Const("response", "await fetch('/api/data', { method: 'GET', headers: { 'Content-Type': 'application/json' }, credentials: 'same-origin' })"),
-								js.Log("'收到響應: ' + response.status"),
-								JSAction{Code: "if (!response.ok) throw new Error('HTTP ' + response.status + ' ' + response.statusText)"},
-								js.Const("apiData", "await response.json()"),
+								js.Fetch("/api/data").Await("apiData"),
 								js.Log("'API 數據:', apiData"),
 								js.Const("container", "document.getElementById('dataContainer')"),
-								JSAction{Code: `if (!container) {
-										console.error('找不到 dataContainer 元素');
-										return;
-									}`},
+								js.If("!container",
+									js.CallMethod("console", "error", "'找不到 dataContainer 元素'"),
+									js.Return(),
+								).End(),
 								JSAction{Code: "container.innerHTML = ''"},
 								js.Const("ul", "document.createElement('ul')"),
 								JSAction{Code: "ul.classList.add('list-group')"},
-								JSAction{Code: `apiData.forEach(function(item) {
-										const li = document.createElement('li');
-										li.classList.add('list-group-item');
-										li.innerHTML = '<strong>' + item.name + '</strong>: ' + item.message;
-										ul.appendChild(li);
-									})`},
+								js.ForEachJS("apiData", "item",
+									js.CallMethod("ul", "appendChild", js.RenderTemplate(
+										Li(Props{"class": "list-group-item"}, Element("strong", nil, "{{name}}"), ": {{message}}"),
+										js.Ident("item"),
+									)),
+								),
 								JSAction{Code: "container.appendChild(ul)"},
 								js.Log("'成功顯示 ' + apiData.length + ' 條數據'"),
 							).Catch("e",
 								js.Log("'獲取數據時出錯:', e.message"),
 								js.Const("container", "document.getElementById('dataContainer')"),
-								JSAction{Code: "if (container) { container.innerHTML = '<div class=\"alert alert-danger\">獲取數據時出錯: ' + e.message + '</div>' }"},
+								js.If("container", JSAction{Code: "container.innerHTML = '<div class=\"alert alert-danger\">獲取數據時出錯: ' + e.message + '</div>'"}).End(),
 							).End(),
 						),
 					}, "獲取數據"),
@@ -219,46 +234,38 @@ func main() {
 						"id":     "postForm",
 						"class":  "mb-3",
 						"action": "#",
-						"onSubmit": js.AsyncDo([]string{"evt"},
-							js.CallMethod("evt", "preventDefault"),
-							js.Log("'表單提交事件觸發'"),
-							js.Try(
-								js.Const("nameValue", "document.getElementById('nameInput') ? document.getElementById('nameInput').value : ''"),
-								js.Const("messageValue", "document.getElementById('messageInput') ? document.getElementById('messageInput').value : ''"),
-								js.Log("'表單數據:', { name: nameValue, message: messageValue }"),
-								js.Const("response", "await fetch('/api/data', { method: 'POST', headers: { 'Content-Type': 'application/json' }, credentials: 'same-origin', body: JSON.stringify({ name: nameValue, message: messageValue }) })"),
-								js.Log("'POST 響應:', response.status"),
-								JSAction{Code: "if (!response.ok) throw new Error('HTTP ' + response.status + ' ' + response.statusText)"},
-								js.Const("postResponse", "await response.json()"),
-								js.Log("'POST 成功:', postResponse"),
-								js.Const("form", "document.getElementById('postForm')"),
-								JSAction{Code: "if (form) form.reset()"},
-								js.Const("respContainer", "document.getElementById('postResponseContainer')"),
-								JSAction{Code: "if (respContainer) { respContainer.innerHTML = '<div class=\"alert alert-success\">表單提交成功！回應包含 ' + postResponse.length + ' 個項目</div>' }"},
-							).Catch("error",
-								js.Log("'提交表單錯誤:', error"),
-								js.Const("respContainer", "document.getElementById('postResponseContainer')"),
-								JSAction{Code: "if (respContainer) { respContainer.innerHTML = '<div class=\"alert alert-danger\">提交表單時出錯: ' + error.message + '</div>' }"},
-							).End(),
+						"onSubmit": js.Do(nil,
+							JSAction{Code: "event.preventDefault()"},
+							js.SubmitForm(js.This(), js.Fetch("/api/data").
+								Then(
+									js.Log("'POST 成功:', data"),
+									js.CallMethod(js.This(), "reset"),
+									js.Const("respContainer", "document.getElementById('postResponseContainer')"),
+									js.If("respContainer", JSAction{Code: "respContainer.textContent = '表單提交成功！回應包含 ' + data.length + ' 個項目'"}).End(),
+								).
+								Catch("error",
+									js.Const("respContainer", "document.getElementById('postResponseContainer')"),
+									js.If("respContainer", JSAction{Code: "respContainer.textContent = '提交表單時出錯: ' + error.message"}).End(),
+								),
+							),
 						),
 					},
-						Div(Props{"class": "mb-3"},
-							Label(Props{"for": "nameInput"}, "姓名"),
-							Input(Props{
-								"id":          "nameInput",
-								"type":        "text",
-								"class":       "form-control",
-								"placeholder": "請輸入姓名",
-							}),
-						),
+						comp.TextField(Props{
+							"id":          "postName",
+							"name":        "name",
+							"label":       "姓名",
+							"placeholder": "請輸入姓名（留空可查看驗證錯誤）",
+						}),
 						Div(Props{"class": "mb-3"},
 							Label(Props{"for": "messageInput"}, "訊息"),
 							Textarea(Props{
 								"id":          "messageInput",
+								"name":        "message",
 								"class":       "form-control",
 								"rows":        "3",
 								"placeholder": "請輸入訊息",
 							}),
+							Div(Props{"class": "form-text text-danger", "data-gvd-error-for": "message"}),
 						),
 						Button(Props{
 							"id":    "submitButton",
@@ -358,11 +365,8 @@ func main() {
 			),
 		)
 
-		// 渲染 HTML 並寫入 HTTP 回應
-		html := Render(doc)
-
-		fmt.Fprint(w, html)
-	})
+		return doc, nil
+	}))
 
 	// 啟動 HTTP 伺服器
 	port := ":8080"
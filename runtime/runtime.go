@@ -1,24 +1,308 @@
 // runtime.go
 package runtime
 
+import (
+	"strings"
+
+	"github.com/TimLai666/go-vdom/internal/jsvalue"
+)
+
 // ClientRuntime 返回客戶端 runtime JavaScript 代碼
-// 這個腳本會自動綁定所有帶有 data-gvd-handler 屬性的元素事件
+// 這個腳本會自動綁定所有帶有 data-gvd-handler 屬性的元素事件，
+// 並處理 data-gvd-get / data-gvd-post 片段請求：
+//   - data-gvd-target：要替換的元素選擇器（預設為元素本身），目標元素必須有 id
+//   - data-gvd-swap：innerHTML（預設）、outerHTML、beforeend 或 afterbegin
+//
+// 表單在 submit 時送出，其他元素在 click 時送出；請求帶有 GVD-Target 標頭，
+// 伺服器（gvd.Handler / gvd.Write）只回應目標 id 的子樹
+//
+// 帶有 data-gvd-live="<端點>" 的元素會以 EventSource 訂閱 gvd.LiveChannel，
+// 依收到的 swap 事件替換或就地更新（morph）目標元素
+//
+// dom.ShadowComponent 宿主（data-gvd-shadow）的 shadow root 內的 handler 與島嶼同樣會被綁定與初始化
+//
+// 以 RenderOptions{Hydrate: true} 渲染的頁面中，帶有 data-gvd-c 的組件由 __gvd.hydrate()
+// 在既有 DOM 上執行初始化（DOM 就緒與替換片段後自動呼叫），不會重新建立元素
+//
+// dom.Island 渲染的島嶼（data-gvd-island）依 data-gvd-load 策略初始化：
+// load 立即、idle 於瀏覽器閒置時、visible 於元素進入視窗時
+//
+// 帶有 data-gvd-view="<端點>" 的容器（live.Mount）會以 WebSocket 連到 gvd/live session：
+// 容器內 data-gvd-click / data-gvd-input / data-gvd-change / data-gvd-submit 元素的事件送往伺服器，
+// 伺服器返回的補丁（dom.Patch）依序套用到容器內的 DOM
+//
+// 客戶端狀態（jsdsl.Signal）以 __gvd.signal / __gvd.set 宣告與更新，綁定的元素隨之同步：
+//   - data-gvd-text="count"：textContent
+//   - data-gvd-attr="href:link,title:user.name"：屬性（null 或 false 時移除）
+//   - data-gvd-class="active:selected,empty:!items.length"：切換 class
+//   - data-gvd-show="open"：hidden 屬性
+//
+// 綁定的值為 signal 名稱或其屬性路徑，前綴 ! 表示取反；未宣告的 signal 不會改動伺服器渲染的內容
+//
+// 表單輔助（jsdsl.FormJSON / jsdsl.SubmitForm）：__gvd.formJSON 將表單序列化為物件，
+// __gvd.formBusy 在送出期間停用送出按鈕，__gvd.showFormErrors 將 422 響應的驗證錯誤
+// 顯示在 TextField / Dropdown 的說明文字或 data-gvd-error-for="<欄位名稱>" 元素中
 func ClientRuntime() string {
 	return `
 (function() {
   // 初始化 __gvd 全域物件
   window.__gvd = window.__gvd || {};
   window.__gvd.handlers = window.__gvd.handlers || {};
+  window.__gvd.live = window.__gvd.live || {};
+  window.__gvd.components = window.__gvd.components || {};
+  window.__gvd.islands = window.__gvd.islands || {};
+
+  // 註冊組件類型的初始化函數工廠：factory(props) 返回 onDOMReady 函數
+  window.__gvd.define = function(type, factory) {
+    window.__gvd.components[type] = factory;
+  };
+
+  // 客戶端狀態：signal 的值與監聽函數
+  window.__gvd.signals = window.__gvd.signals || {};
+  window.__gvd.watchers = window.__gvd.watchers || {};
+  var bindingSelector = '[data-gvd-text],[data-gvd-attr],[data-gvd-class],[data-gvd-show]';
+
+  // 宣告 signal；已宣告時保留現有的值，重複執行同一段腳本不會重設狀態
+  window.__gvd.signal = function(name, initial) {
+    var signals = window.__gvd.signals;
+    if (!Object.prototype.hasOwnProperty.call(signals, name)) signals[name] = initial;
+    if (document.readyState !== 'loading') applyBindings(document, name);
+    return signals[name];
+  };
+
+  window.__gvd.get = function(name) {
+    return window.__gvd.signals[name];
+  };
+
+  // 更新 signal，同步綁定的元素並通知監聽函數
+  window.__gvd.set = function(name, value) {
+    var prev = window.__gvd.signals[name];
+    window.__gvd.signals[name] = value;
+    applyBindings(document, name);
+    (window.__gvd.watchers[name] || []).slice().forEach(function(fn) {
+      try {
+        fn(value, prev);
+      } catch (err) {
+        console.error('gvd: signal watcher failed: ' + name, err);
+      }
+    });
+    return value;
+  };
+
+  // 監聽 signal 的變化，返回取消監聽的函數
+  window.__gvd.watch = function(name, fn) {
+    var list = window.__gvd.watchers[name] = window.__gvd.watchers[name] || [];
+    list.push(fn);
+    return function() {
+      var i = list.indexOf(fn);
+      if (i >= 0) list.splice(i, 1);
+    };
+  };
+
+  function bindingName(path) {
+    return path.replace(/^!/, '').split('.')[0].trim();
+  }
+
+  // 綁定是否需要更新：指定 name 時只處理該 signal，否則處理所有已宣告的 signal
+  function bindingMatches(path, name) {
+    var n = bindingName(path);
+    return name ? n === name : Object.prototype.hasOwnProperty.call(window.__gvd.signals, n);
+  }
+
+  function readBinding(path) {
+    var negate = path.charAt(0) === '!';
+    var parts = path.replace(/^!/, '').split('.');
+    var value = window.__gvd.signals[parts[0].trim()];
+    for (var i = 1; i < parts.length && value != null; i++) value = value[parts[i].trim()];
+    return negate ? !value : value;
+  }
+
+  // 解析 "key:path,key:path" 形式的綁定
+  function bindingPairs(attr) {
+    return (attr || '').split(',').map(function(pair) {
+      var i = pair.indexOf(':');
+      return [pair.slice(0, i).trim(), pair.slice(i + 1).trim()];
+    }).filter(function(pair) {
+      return pair[0] && pair[1];
+    });
+  }
+
+  function applyBinding(el, name) {
+    var text = el.getAttribute('data-gvd-text');
+    if (text && bindingMatches(text, name)) {
+      var value = readBinding(text);
+      el.textContent = value == null ? '' : String(value);
+    }
+    var show = el.getAttribute('data-gvd-show');
+    if (show && bindingMatches(show, name)) el.hidden = !readBinding(show);
+    bindingPairs(el.getAttribute('data-gvd-attr')).forEach(function(pair) {
+      if (!bindingMatches(pair[1], name)) return;
+      var value = readBinding(pair[1]);
+      if (value == null || value === false) {
+        el.removeAttribute(pair[0]);
+      } else {
+        el.setAttribute(pair[0], value === true ? '' : String(value));
+      }
+    });
+    bindingPairs(el.getAttribute('data-gvd-class')).forEach(function(pair) {
+      if (bindingMatches(pair[1], name)) el.classList.toggle(pair[0], !!readBinding(pair[1]));
+    });
+  }
+
+  // 更新 root 內綁定到 name 的元素；省略 name 時更新所有綁定到已宣告 signal 的元素
+  function applyBindings(root, name) {
+    root = root || document;
+    var elements = queryAll(root, bindingSelector);
+    if (root.nodeType === 1 && root.matches(bindingSelector)) elements.unshift(root);
+    elements.forEach(function(el) {
+      applyBinding(el, name);
+    });
+  }
+  window.__gvd.applyBindings = applyBindings;
+
+  // 將表單欄位序列化為物件：name="a.b" 成為巢狀物件，重複的名稱或以 [] 結尾的名稱成為陣列；檔案欄位略過
+  window.__gvd.formJSON = function(form) {
+    var out = {};
+    new FormData(form).forEach(function(value, name) {
+      if (typeof value !== 'string') return;
+      var keys = name.split('.');
+      if (keys.some(function(key) { return key === '__proto__' || key === 'constructor' || key === 'prototype'; })) return;
+      var obj = out;
+      for (var i = 0; i < keys.length - 1; i++) {
+        var next = obj[keys[i]];
+        if (next === null || typeof next !== 'object' || Array.isArray(next)) next = obj[keys[i]] = {};
+        obj = next;
+      }
+      var key = keys[keys.length - 1];
+      if (key.slice(-2) === '[]') {
+        key = key.slice(0, -2);
+        if (!Array.isArray(obj[key])) obj[key] = [];
+        obj[key].push(value);
+      } else if (Object.prototype.hasOwnProperty.call(obj, key)) {
+        if (!Array.isArray(obj[key])) obj[key] = [obj[key]];
+        obj[key].push(value);
+      } else {
+        obj[key] = value;
+      }
+    });
+    return out;
+  };
+
+  // 送出期間停用表單的送出按鈕（包含以 form 屬性關聯的按鈕），結束後只恢復由此停用的按鈕
+  window.__gvd.formBusy = function(form, busy) {
+    Array.prototype.forEach.call(form.elements, function(el) {
+      if (el.type !== 'submit') return;
+      if (busy && !el.disabled) {
+        el.disabled = true;
+        el.__gvdBusy = true;
+      } else if (!busy && el.__gvdBusy) {
+        el.disabled = false;
+        el.__gvdBusy = false;
+      }
+    });
+    if (busy) {
+      form.setAttribute('aria-busy', 'true');
+    } else {
+      form.removeAttribute('aria-busy');
+    }
+  };
+
+  // 將 { "a": { "b": "訊息" } } 攤平為 { "a.b": "訊息" }；陣列視為同一欄位的多則訊息
+  function flattenErrors(errors, prefix, out) {
+    Object.keys(errors || {}).forEach(function(key) {
+      var value = errors[key];
+      var name = prefix ? prefix + '.' + key : key;
+      if (value !== null && typeof value === 'object' && !Array.isArray(value)) {
+        flattenErrors(value, name, out);
+      } else {
+        out[name] = [].concat(value).join(' ');
+      }
+    });
+    return out;
+  }
+
+  // 清除上次顯示的驗證錯誤，恢復原本的說明文字
+  window.__gvd.clearFormErrors = function(form) {
+    (form.__gvdErrors || []).forEach(function(restore) {
+      restore();
+    });
+    form.__gvdErrors = [];
+  };
+
+  // 顯示驗證錯誤；errors 為 { 欄位名稱: 訊息 } 或 { errors: {...} }，返回攤平後的錯誤
+  window.__gvd.showFormErrors = function(form, errors) {
+    window.__gvd.clearFormErrors(form);
+    var flat = flattenErrors(errors && errors.errors && typeof errors.errors === 'object' ? errors.errors : errors, '', {});
+    var restores = form.__gvdErrors;
+    Object.keys(flat).forEach(function(name) {
+      var field = form.elements.namedItem(name) || form.elements.namedItem(name + '[]');
+      if (field && !field.nodeType && field.length) field = field[0];
+      var target = Array.prototype.find.call(form.querySelectorAll('[data-gvd-error-for]'), function(el) {
+        return el.getAttribute('data-gvd-error-for') === name;
+      });
+      if (!target && field) {
+        var box = field.closest('.textfield-container,.dropdown-container');
+        target = box && box.querySelector('.textfield-help-text,.dropdown-help-text');
+      }
+      if (field && field.setAttribute) {
+        var invalid = field.getAttribute('aria-invalid');
+        field.setAttribute('aria-invalid', 'true');
+        restores.push(function() {
+          if (invalid === null) {
+            field.removeAttribute('aria-invalid');
+          } else {
+            field.setAttribute('aria-invalid', invalid);
+          }
+        });
+      }
+      if (target) {
+        var text = target.textContent, color = target.style.color, display = target.style.display;
+        target.textContent = flat[name];
+        target.style.color = '#ef4444';
+        target.style.display = 'block';
+        restores.push(function() {
+          target.textContent = text;
+          target.style.color = color;
+          target.style.display = display;
+        });
+      }
+    });
+    return flat;
+  };
+
+  // 請求失敗時的處理：422 響應的 JSON 主體作為驗證錯誤顯示，並以 err.errors 提供給後續的處理器；錯誤會繼續拋出
+  window.__gvd.formError = function(form, err) {
+    if (!err || err.status !== 422 || !err.response) return Promise.reject(err);
+    return err.response.json().catch(function() {
+      return {};
+    }).then(function(body) {
+      err.errors = window.__gvd.showFormErrors(form, body);
+      throw err;
+    });
+  };
+
+  // 查找 root 內符合選擇器的元素，包含 dom.ShadowComponent 宿主（data-gvd-shadow）的 shadow root 內的元素
+  function queryAll(root, selector) {
+    var found = Array.prototype.slice.call(root.querySelectorAll(selector));
+    root.querySelectorAll('[data-gvd-shadow]').forEach(function(host) {
+      if (host.shadowRoot) found = found.concat(queryAll(host.shadowRoot, selector));
+    });
+    return found;
+  }
 
   // 綁定所有帶有 data-gvd-handler 屬性的元素
   function bindHandlers() {
     // 查找所有帶有 data-gvd-handler 屬性的元素
-    var elements = document.querySelectorAll('[data-gvd-handler]');
+    var elements = queryAll(document, '[data-gvd-handler]');
 
     elements.forEach(function(el) {
       var handlerAttr = el.getAttribute('data-gvd-handler');
       if (!handlerAttr) return;
 
+      // 已綁定過的元素跳過，避免替換片段後重新綁定造成重複觸發
+      if (el.__gvdBound === handlerAttr) return;
+      el.__gvdBound = handlerAttr;
+
       // 解析 handlerID|eventType 格式
       var parts = handlerAttr.split('|');
       if (parts.length !== 2) return;
@@ -56,17 +340,431 @@ func ClientRuntime() string {
   // 在 DOM 準備就緒時綁定所有 handler
   if (document.readyState === 'loading') {
     document.addEventListener('DOMContentLoaded', function() {
-      bindHandlers();
+      hydrate();
+      connectLive();
+      connectViews();
     });
   } else {
     // DOM 已經加載完成，立即綁定
+    hydrate();
+    connectLive();
+    connectViews();
+  }
+
+  // 解析伺服器輸出的 props JSON；只以 JSON.parse 解析，不執行任何代碼
+  // {"$gvd":"date"|"bytes"|"number","v":...} 標記物件還原為 Date、Uint8Array 與 NaN / Infinity
+  function reviveProp(key, value) {
+    if (value === null || typeof value !== 'object' || Array.isArray(value) || typeof value.$gvd !== 'string') return value;
+    switch (value.$gvd) {
+      case 'date':
+        return new Date(value.v);
+      case 'bytes':
+        return new Uint8Array(value.v);
+      case 'number':
+        return Number(value.v);
+    }
+    return value;
+  }
+  function parseProps(text) {
+    if (!text) return {};
+    try {
+      return JSON.parse(text, reviveProp);
+    } catch (err) {
+      console.error('gvd: invalid props', err);
+      return {};
+    }
+  }
+
+  // 執行尚未水合的 data-gvd-c 組件的初始化函數（每個元素只執行一次），並綁定 handler
+  // 組件類型尚未定義時略過，之後再次呼叫 hydrate 時處理
+  function hydrate(root) {
+    root = root || document;
+    var elements = Array.prototype.slice.call(root.querySelectorAll('[data-gvd-c]'));
+    if (root.nodeType === 1 && root.hasAttribute('data-gvd-c')) elements.unshift(root);
+    elements.forEach(function(el) {
+      var type = el.getAttribute('data-gvd-c');
+      var factory = window.__gvd.components[type];
+      if (el.__gvdHydrated || !factory) return;
+      el.__gvdHydrated = true;
+      try {
+        factory(parseProps(el.getAttribute('data-gvd-p')))();
+      } catch (err) {
+        console.error('gvd: failed to hydrate component ' + type, err);
+      }
+    });
+    startIslands(root);
     bindHandlers();
+    applyBindings(root);
   }
+  window.__gvd.hydrate = hydrate;
+
+  // 依各島嶼的 data-gvd-load 策略呼叫已註冊的 init(el, props)，每個元素只初始化一次
+  function startIslands(root) {
+    queryAll(root || document, '[data-gvd-island]').forEach(function(el) {
+      var name = el.getAttribute('data-gvd-island');
+      var init = window.__gvd.islands[name];
+      if (el.__gvdIsland || !init) return;
+      el.__gvdIsland = true;
+
+      var run = function() {
+        try {
+          init(el, parseProps(el.getAttribute('data-gvd-props')));
+        } catch (err) {
+          console.error('gvd: failed to start island ' + name, err);
+        }
+      };
+      switch (el.getAttribute('data-gvd-load')) {
+        case 'idle':
+          if (window.requestIdleCallback) {
+            window.requestIdleCallback(run);
+          } else {
+            setTimeout(run, 200);
+          }
+          break;
+        case 'visible':
+          if (!window.IntersectionObserver) {
+            run();
+            break;
+          }
+          var observer = new IntersectionObserver(function(entries) {
+            if (entries.some(function(entry) { return entry.isIntersecting; })) {
+              observer.disconnect();
+              run();
+            }
+          });
+          observer.observe(el);
+          break;
+        default:
+          run();
+      }
+    });
+  }
+  window.__gvd.startIslands = startIslands;
 
   // 提供一個公開的方法來重新綁定 handler（用於動態內容）
   window.__gvd.rebind = function() {
     bindHandlers();
   };
+
+  // 以 innerHTML 插入的 <script> 不會執行，替換為新建的 script 元素使其執行
+  function runScripts(node) {
+    if (node.nodeType !== 1) return;
+    var scripts = node.tagName === 'SCRIPT' ? [node] : node.querySelectorAll('script');
+    Array.prototype.forEach.call(scripts, function(old) {
+      var script = document.createElement('script');
+      Array.prototype.forEach.call(old.attributes, function(attr) {
+        script.setAttribute(attr.name, attr.value);
+      });
+      script.textContent = old.textContent;
+      old.parentNode.replaceChild(script, old);
+    });
+  }
+
+  // 將 HTML 片段依 mode 放入 target
+  // 伺服器返回的是目標元素本身；除 outerHTML 外，只取其子節點放入
+  function swap(target, html, mode) {
+    var tpl = document.createElement('template');
+    tpl.innerHTML = html.trim();
+    var frag = tpl.content;
+    var root = frag.firstElementChild;
+    if (mode !== 'outerHTML' && root && target.id && root.id === target.id) {
+      frag = document.createDocumentFragment();
+      while (root.firstChild) frag.appendChild(root.firstChild);
+    }
+
+    if (mode === 'morph') {
+      morphChildren(target, frag);
+      hydrate();
+      connectLive();
+      return;
+    }
+
+    var inserted = Array.prototype.slice.call(frag.childNodes);
+    switch (mode) {
+      case 'outerHTML':
+        target.parentNode.replaceChild(frag, target);
+        break;
+      case 'beforeend':
+        target.appendChild(frag);
+        break;
+      case 'afterbegin':
+        target.insertBefore(frag, target.firstChild);
+        break;
+      default:
+        target.innerHTML = '';
+        target.appendChild(frag);
+    }
+    inserted.forEach(runScripts);
+    hydrate();
+    connectLive();
+  }
+
+  // 就地比對更新 parent 的子節點：同位置且同類型的節點保留並同步屬性與文字，其餘替換
+  function morphChildren(parent, source) {
+    var next = Array.prototype.slice.call(source.childNodes);
+    for (var i = 0; i < next.length; i++) {
+      var cur = parent.childNodes[i];
+      if (cur) {
+        morphNode(cur, next[i]);
+      } else {
+        parent.appendChild(next[i]);
+        runScripts(next[i]);
+      }
+    }
+    while (parent.childNodes.length > next.length) {
+      parent.removeChild(parent.lastChild);
+    }
+  }
+
+  function morphNode(cur, next) {
+    if (cur.nodeType !== next.nodeType || cur.nodeName !== next.nodeName) {
+      cur.parentNode.replaceChild(next, cur);
+      runScripts(next);
+      return;
+    }
+    if (cur.nodeType !== 1) {
+      if (cur.nodeValue !== next.nodeValue) cur.nodeValue = next.nodeValue;
+      return;
+    }
+    // 內容相同的腳本不重新執行
+    if (cur.tagName === 'SCRIPT') {
+      if (cur.textContent !== next.textContent) {
+        cur.parentNode.replaceChild(next, cur);
+        runScripts(next);
+      }
+      return;
+    }
+
+    syncAttributes(cur, next);
+    // 使用者正在輸入的欄位保留目前的值
+    if (cur !== document.activeElement && /^(INPUT|TEXTAREA|SELECT)$/.test(cur.tagName)) {
+      cur.value = next.value;
+    }
+    morphChildren(cur, next);
+  }
+
+  // 以 next 的屬性取代 cur 的屬性
+  function syncAttributes(cur, next) {
+    Array.prototype.slice.call(cur.attributes).forEach(function(attr) {
+      if (!next.hasAttribute(attr.name)) cur.removeAttribute(attr.name);
+    });
+    Array.prototype.forEach.call(next.attributes, function(attr) {
+      if (cur.getAttribute(attr.name) !== attr.value) cur.setAttribute(attr.name, attr.value);
+    });
+  }
+
+  // 訂閱頁面上所有 data-gvd-live 端點，同一端點只建立一個 EventSource
+  function connectLive() {
+    if (!window.EventSource) return;
+    document.querySelectorAll('[data-gvd-live]').forEach(function(el) {
+      var url = el.getAttribute('data-gvd-live');
+      if (!url || window.__gvd.live[url]) return;
+      var source = new EventSource(url);
+      source.addEventListener('swap', function(evt) {
+        var update = JSON.parse(evt.data);
+        var target = document.getElementById(update.target);
+        if (target) swap(target, update.html, update.swap);
+      });
+      window.__gvd.live[url] = source;
+    });
+  }
+
+  function parseHTML(html) {
+    var tpl = document.createElement('template');
+    tpl.innerHTML = html;
+    return tpl.content;
+  }
+
+  // 插入片段並執行其中的腳本
+  function insertHTML(html, insert) {
+    var frag = parseHTML(html);
+    var nodes = Array.prototype.slice.call(frag.childNodes);
+    insert(frag);
+    nodes.forEach(runScripts);
+  }
+
+  // 依序套用 dom.Diff 產生的補丁；路徑為容器第一個子元素之下的子元素索引
+  function applyPatches(container, patches) {
+    patches.forEach(function(p) {
+      if (p.path.length === 0 && p.op === 'replace') {
+        insertHTML(p.html, function(frag) {
+          container.innerHTML = '';
+          container.appendChild(frag);
+        });
+        return;
+      }
+      var el = container.firstElementChild;
+      for (var i = 0; el && i < p.path.length; i++) el = el.children[p.path[i]];
+      if (!el) {
+        console.warn('gvd: patch path not found', p.path);
+        return;
+      }
+      switch (p.op) {
+        case 'replace':
+          insertHTML(p.html, function(frag) { el.parentNode.replaceChild(frag, el); });
+          break;
+        case 'html':
+          insertHTML(p.html, function(frag) {
+            el.innerHTML = '';
+            el.appendChild(frag);
+          });
+          break;
+        case 'attrs':
+          var next = parseHTML(p.html).firstElementChild;
+          if (next) {
+            syncAttributes(el, next);
+            if ('value' in el && el !== document.activeElement) el.value = next.getAttribute('value') || '';
+          }
+          break;
+        case 'append':
+          insertHTML(p.html, function(frag) { el.appendChild(frag); });
+          break;
+        case 'truncate':
+          while (el.children.length > (p.count || 0)) el.removeChild(el.lastElementChild);
+          break;
+      }
+    });
+    hydrate();
+  }
+
+  // 為頁面上每個 data-gvd-view 容器建立 WebSocket 連線
+  function connectViews() {
+    if (!window.WebSocket) return;
+    document.querySelectorAll('[data-gvd-view]').forEach(function(container) {
+      if (!container.__gvdSocket) openView(container);
+    });
+  }
+
+  function openView(container) {
+    var url = new URL(container.getAttribute('data-gvd-view'), location.href);
+    url.protocol = url.protocol === 'https:' ? 'wss:' : 'ws:';
+    var socket = new WebSocket(url.href);
+    container.__gvdSocket = socket;
+    socket.onmessage = function(msg) {
+      applyPatches(container, JSON.parse(msg.data).patches || []);
+    };
+    socket.onclose = function() {
+      // 連線中斷時稍後重連；伺服器會建立新的 session 並送出完整內容
+      setTimeout(function() {
+        if (container.isConnected) openView(container);
+      }, 1000);
+    };
+  }
+
+  // 將 live view 容器內元素的事件送往伺服器
+  function sendViewEvent(evt) {
+    var attr = 'data-gvd-' + evt.type;
+    var el = evt.target.closest && evt.target.closest('[' + attr + ']');
+    var container = el && el.closest('[data-gvd-view]');
+    if (!container) return;
+    // 表單一律由伺服器處理，連線尚未建立時也不做傳統提交
+    if (evt.type === 'submit') evt.preventDefault();
+    var socket = container.__gvdSocket;
+    if (!socket || socket.readyState !== 1) return;
+
+    var ev = { handler: el.getAttribute(attr), type: evt.type };
+    if (evt.type === 'submit') {
+      ev.form = {};
+      new FormData(el).forEach(function(value, key) {
+        if (typeof value === 'string') ev.form[key] = value;
+      });
+    } else if (evt.type !== 'click' && 'value' in el) {
+      ev.value = String(el.value);
+    }
+    socket.send(JSON.stringify(ev));
+  }
+
+  // 依元素上的 data-gvd-* 屬性送出片段請求並替換目標
+  function request(el) {
+    var method = el.hasAttribute('data-gvd-post') ? 'POST' : 'GET';
+    var url = el.getAttribute(method === 'POST' ? 'data-gvd-post' : 'data-gvd-get') || location.href;
+    var selector = el.getAttribute('data-gvd-target');
+    var target = selector ? document.querySelector(selector) : el;
+    if (!target || !target.id) {
+      console.warn('gvd: fragment target must exist and have an id: ' + (selector || el.tagName));
+      return Promise.resolve();
+    }
+    var mode = el.getAttribute('data-gvd-swap') || 'innerHTML';
+
+    var init = { method: method, headers: { 'GVD-Target': '#' + target.id } };
+    var form = el.tagName === 'FORM' ? el : el.form;
+    if (form) {
+      var data = new FormData(form);
+      if (method === 'POST') {
+        init.body = data;
+      } else {
+        url += (url.indexOf('?') < 0 ? '?' : '&') + new URLSearchParams(data).toString();
+      }
+    }
+
+    return fetch(url, init).then(function(res) {
+      if (!res.ok) throw new Error(res.status + ' ' + res.statusText);
+      return res.text();
+    }).then(function(html) {
+      swap(target, html, mode);
+    }).catch(function(err) {
+      console.warn('gvd: fragment request failed: ' + url, err);
+    });
+  }
+
+  // 以事件委派處理片段請求，動態插入的元素無需重新綁定
+  // runtime 被載入多次時只註冊一次
+  var fragmentSelector = '[data-gvd-get],[data-gvd-post]';
+  if (!window.__gvd.request) {
+    document.addEventListener('click', function(evt) {
+      var el = evt.target.closest && evt.target.closest(fragmentSelector);
+      if (!el || el.tagName === 'FORM') return;
+      evt.preventDefault();
+      window.__gvd.request(el);
+    });
+    document.addEventListener('submit', function(evt) {
+      var form = evt.target;
+      if (!form.matches || !form.matches(fragmentSelector)) return;
+      evt.preventDefault();
+      window.__gvd.request(form);
+    });
+    ['click', 'input', 'change', 'submit'].forEach(function(type) {
+      document.addEventListener(type, sendViewEvent);
+    });
+  }
+
+  window.__gvd.swap = swap;
+  window.__gvd.request = request;
+  window.__gvd.applyPatches = applyPatches;
+})();
+`
+}
+
+// DevReloadEnv 是開發模式的環境變數名稱
+// `gvd dev` 啟動使用者的伺服器時會將其設為 reload 事件的 SSE 端點，
+// Document 只在此變數非空時注入 DevReloadScript
+const DevReloadEnv = "GVD_DEV_RELOAD"
+
+// DevReloadScript 返回開發模式的自動重新整理腳本
+// 腳本透過 EventSource 訂閱 endpoint，收到 reload 事件時重新載入頁面；
+// 連線中斷時 EventSource 會自動重連
+// 重新啟動的伺服器可能尚未開始監聽，因此先輪詢目前頁面，直到伺服器回應（或約 10 秒後）才重新載入
+func DevReloadScript(endpoint string) string {
+	var src strings.Builder
+	jsvalue.QuoteSingle(&src, endpoint)
+	return `
+(function() {
+  if (!window.EventSource || window.__gvdDevReload) return;
+  window.__gvdDevReload = true;
+  var source = new EventSource(` + src.String() + `);
+  function reloadWhenReady(tries) {
+    fetch(location.href, { method: 'HEAD', cache: 'no-store' }).then(function() {
+      location.reload();
+    }, function() {
+      if (tries > 0) setTimeout(function() { reloadWhenReady(tries - 1); }, 100);
+      else location.reload();
+    });
+  }
+  source.addEventListener('reload', function() {
+    source.close();
+    if (window.fetch) reloadWhenReady(100);
+    else location.reload();
+  });
 })();
 `
 }
@@ -0,0 +1,190 @@
+// runtime_test.go
+package runtime
+
+import (
+	"encoding/json"
+	"os/exec"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// domHarness 以最小的 DOM 替身執行 ClientRuntime，再執行測試腳本；腳本以 done(result) 回報 JSON 結果
+const domHarness = `
+var noop = function() {};
+global.window = global;
+global.document = { readyState: 'complete', addEventListener: noop, querySelectorAll: function() { return []; } };
+global.MutationObserver = function() { this.observe = noop; };
+global.FormData = function(form) { this.entries = form.entries; };
+FormData.prototype.forEach = function(fn) {
+  this.entries.forEach(function(e) { fn(e[1], e[0]); });
+};
+function el(attrs) {
+  return {
+    nodeType: 1, attrs: attrs || {}, style: { color: '', display: '' }, textContent: '',
+    getAttribute: function(k) { return k in this.attrs ? this.attrs[k] : null; },
+    setAttribute: function(k, v) { this.attrs[k] = String(v); },
+    removeAttribute: function(k) { delete this.attrs[k]; },
+    closest: function() { return this.box || null; },
+    querySelector: function() { return this.help || null; }
+  };
+}
+function done(result) { process.stdout.write(JSON.stringify(result)); }
+`
+
+// runNode 執行 ClientRuntime 與 script，返回 done 回報的結果；沒有安裝 node 時略過測試
+func runNode(t *testing.T, script string) any {
+	t.Helper()
+	node, err := exec.LookPath("node")
+	if err != nil {
+		t.Skip("node is not installed")
+	}
+	cmd := exec.Command(node, "-")
+	cmd.Stdin = strings.NewReader(domHarness + ClientRuntime() + script)
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("node: %v\n%s", err, stderr.String())
+	}
+	var result any
+	if err := json.Unmarshal(out, &result); err != nil {
+		t.Fatalf("invalid result %q: %v", out, err)
+	}
+	return result
+}
+
+func expectJSON(t *testing.T, got any, want string) {
+	t.Helper()
+	var w any
+	if err := json.Unmarshal([]byte(want), &w); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, w) {
+		g, _ := json.Marshal(got)
+		t.Errorf("got  %s\nwant %s", g, want)
+	}
+}
+
+func TestFormJSON(t *testing.T) {
+	got := runNode(t, `
+done(window.__gvd.formJSON({ entries: [
+  ['user.name', 'Tim'], ['user.address.city', 'Taipei'],
+  ['tags', 'a'], ['tags', 'b'], ['tags', 'c'],
+  ['ids[]', '1'],
+  ['note', 'x'], ['note.text', 'y'],
+  ['__proto__.polluted', 'yes'], ['avatar', { size: 1 }]
+] }));
+`)
+	// 巢狀名稱成為物件，重複名稱與 [] 成為陣列；物件欄位覆蓋同名的純值，__proto__ 與檔案被略過
+	expectJSON(t, got, `{
+		"user": {"name": "Tim", "address": {"city": "Taipei"}},
+		"tags": ["a", "b", "c"],
+		"ids": ["1"],
+		"note": {"text": "y"}
+	}`)
+}
+
+func TestFormErrorMaps422ToFields(t *testing.T) {
+	got := runNode(t, `
+var email = el({ name: 'user.email', 'aria-invalid': 'false' });
+var help = el();
+help.textContent = '我們不會公開您的電子郵件';
+email.box = el();
+email.box.help = help;
+var tags = el({ name: 'tags' });
+var tagsError = el({ 'data-gvd-error-for': 'tags' });
+var fields = { 'user.email': email, 'tags': tags };
+var form = {
+  elements: { namedItem: function(name) { return fields[name] || null; } },
+  querySelectorAll: function() { return [tagsError]; }
+};
+var err = new Error('HTTP 422');
+err.status = 422;
+err.response = { json: function() { return Promise.resolve({ errors: { user: { email: '格式錯誤' }, tags: ['太多', '重複'] } }); } };
+var other = new Error('HTTP 500');
+other.status = 500;
+window.__gvd.formError(form, err).catch(function(e) {
+  var shown = {
+    same: e === err,
+    errors: e.errors,
+    help: help.textContent,
+    color: help.style.color,
+    emailInvalid: email.getAttribute('aria-invalid'),
+    tagsInvalid: tags.getAttribute('aria-invalid'),
+    tagsError: tagsError.textContent
+  };
+  window.__gvd.clearFormErrors(form);
+  var cleared = {
+    help: help.textContent,
+    color: help.style.color,
+    emailInvalid: email.getAttribute('aria-invalid'),
+    tagsInvalid: tags.getAttribute('aria-invalid'),
+    tagsError: tagsError.textContent
+  };
+  return window.__gvd.formError(form, other).catch(function(e) {
+    done({ shown: shown, cleared: cleared, passthrough: e === other && !e.errors });
+  });
+});
+`)
+	expectJSON(t, got, `{
+		"shown": {
+			"same": true,
+			"errors": {"user.email": "格式錯誤", "tags": "太多 重複"},
+			"help": "格式錯誤",
+			"color": "#ef4444",
+			"emailInvalid": "true",
+			"tagsInvalid": "true",
+			"tagsError": "太多 重複"
+		},
+		"cleared": {
+			"help": "我們不會公開您的電子郵件",
+			"color": "",
+			"emailInvalid": "false",
+			"tagsInvalid": null,
+			"tagsError": ""
+		},
+		"passthrough": true
+	}`)
+}
+
+func TestDevReloadScriptQuotesEndpoint(t *testing.T) {
+	got := DevReloadScript("/__gvd/reload?x='</script>")
+	if !strings.Contains(got, `new EventSource('/__gvd/reload?x=\'\u003c/script\u003e')`) {
+		t.Errorf("endpoint should be quoted with jsvalue.QuoteSingle:\n%s", got)
+	}
+}
+
+func TestHydratePropsAreParsedAsJSONOnly(t *testing.T) {
+	got := runNode(t, `
+var received = [];
+window.__gvd.define('card', function(p) { return function() { received.push(p); }; });
+function component(props) {
+  var node = el({ 'data-gvd-c': 'card', 'data-gvd-p': props });
+  node.hasAttribute = function(k) { return k in this.attrs; };
+  node.querySelectorAll = function() { return []; };
+  node.matches = function() { return false; };
+  return node;
+}
+window.pwned = false;
+window.__gvd.hydrate(component('{"at":{"$gvd":"date","v":"2025-05-01T12:00:00Z"},"b":{"$gvd":"bytes","v":[1,2]},"n":{"$gvd":"number","v":"-Infinity"},"s":"x"}'));
+window.__gvd.hydrate(component('(window.pwned = true, {})'));
+var p = received[0];
+done({
+  at: p.at instanceof Date && p.at.toISOString(),
+  bytes: p.b instanceof Uint8Array && Array.from(p.b),
+  n: p.n === -Infinity,
+  s: p.s,
+  pwned: window.pwned,
+  invalid: received[1]
+});
+`)
+	expectJSON(t, got, `{
+		"at": "2025-05-01T12:00:00.000Z",
+		"bytes": [1, 2],
+		"n": true,
+		"s": "x",
+		"pwned": false,
+		"invalid": {}
+	}`)
+}
@@ -0,0 +1,141 @@
+// dom_js.go
+//go:build js && wasm
+
+package wasm
+
+import (
+	"fmt"
+	"syscall/js"
+
+	"github.com/TimLai666/go-vdom/dom"
+)
+
+// browserDOM 以 syscall/js 操作瀏覽器的 document
+type browserDOM struct {
+	document js.Value
+}
+
+// Document 返回瀏覽器 document 的 DOM 實作
+func Document() DOM {
+	return browserDOM{document: js.Global().Get("document")}
+}
+
+// MountID 在 id 指定的元素中掛載 render 的結果
+// 用法（main 結尾以 select {} 保持程式執行，事件處理器才能被呼叫）：
+//
+//	root, err := wasm.MountID("app", render)
+func MountID(id string, render func() dom.VNode) (*Root, error) {
+	d := Document().(browserDOM)
+	container := d.document.Call("getElementById", id)
+	if container.IsNull() {
+		return nil, fmt.Errorf("wasm: element #%s not found", id)
+	}
+	return Mount(d, container, render), nil
+}
+
+func (d browserDOM) CreateElement(tag string) Node {
+	return d.document.Call("createElement", tag)
+}
+
+func (d browserDOM) CreateText(text string) Node {
+	return d.document.Call("createTextNode", text)
+}
+
+func (d browserDOM) SetText(n Node, text string) {
+	n.(js.Value).Set("nodeValue", text)
+}
+
+// ParseHTML 以 <template> 解析 HTML 片段；與 innerHTML 相同，其中的 <script> 不會執行
+func (d browserDOM) ParseHTML(html string) []Node {
+	tpl := d.document.Call("createElement", "template")
+	tpl.Set("innerHTML", html)
+	children := tpl.Get("content").Get("childNodes")
+	nodes := make([]Node, children.Length())
+	for i := range nodes {
+		nodes[i] = children.Index(i)
+	}
+	return nodes
+}
+
+// SetAttribute 設定屬性；value 與 checked 同時設定元素屬性，使用者輸入後也能更新目前的值
+func (d browserDOM) SetAttribute(el Node, name, value string) {
+	v := el.(js.Value)
+	v.Call("setAttribute", name, value)
+	switch name {
+	case "value":
+		v.Set("value", value)
+	case "checked", "selected":
+		v.Set(name, true)
+	}
+}
+
+func (d browserDOM) RemoveAttribute(el Node, name string) {
+	v := el.(js.Value)
+	v.Call("removeAttribute", name)
+	switch name {
+	case "value":
+		v.Set("value", "")
+	case "checked", "selected":
+		v.Set(name, false)
+	}
+}
+
+func (d browserDOM) InsertBefore(parent, child, ref Node) {
+	r := js.Null()
+	if ref != nil {
+		r = ref.(js.Value)
+	}
+	parent.(js.Value).Call("insertBefore", child.(js.Value), r)
+}
+
+func (d browserDOM) RemoveChild(parent, child Node) {
+	parent.(js.Value).Call("removeChild", child.(js.Value))
+}
+
+func (d browserDOM) ReplaceChild(parent, newChild, oldChild Node) {
+	parent.(js.Value).Call("replaceChild", newChild.(js.Value), oldChild.(js.Value))
+}
+
+func (d browserDOM) Listen(el Node, event string, handler func(Event)) func() {
+	v := el.(js.Value)
+	fn := js.FuncOf(func(this js.Value, args []js.Value) any {
+		handler(toEvent(args[0]))
+		return nil
+	})
+	v.Call("addEventListener", event, fn)
+	return func() {
+		v.Call("removeEventListener", event, fn)
+		fn.Release()
+	}
+}
+
+// Script 以 new Function('event', code) 編譯處理器，並以元素作為 this 呼叫
+func (d browserDOM) Script(el Node, code string) func(Event) {
+	fn := js.Global().Get("Function").New("event", code)
+	v := el.(js.Value)
+	return func(ev Event) {
+		var jsEvent any = js.Undefined()
+		if raw, ok := ev.raw.(js.Value); ok {
+			jsEvent = raw
+		}
+		fn.Call("call", v, jsEvent)
+	}
+}
+
+// toEvent 將瀏覽器事件轉為 Event
+func toEvent(e js.Value) Event {
+	ev := Event{Type: e.Get("type").String(), raw: e}
+	target := e.Get("target")
+	ev.Target = target
+	if value := target.Get("value"); value.Type() == js.TypeString {
+		ev.Value = value.String()
+	}
+	if checked := target.Get("checked"); checked.Type() == js.TypeBoolean {
+		ev.Checked = checked.Bool()
+	}
+	if key := e.Get("key"); key.Type() == js.TypeString {
+		ev.Key = key.String()
+	}
+	ev.PreventDefault = func() { e.Call("preventDefault") }
+	return ev
+}
@@ -0,0 +1,401 @@
+// wasm.go
+package wasm
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/TimLai666/go-vdom/dom"
+)
+
+// Node 是 DOM 實作中的節點（瀏覽器中為 js.Value）
+type Node interface{}
+
+// Event 是傳給 Go 事件處理器的事件資訊
+type Event struct {
+	Type           string
+	Target         Node
+	Value          string // 目標元素的 value（表單元素）
+	Checked        bool   // 目標元素的 checked（checkbox / radio）
+	Key            string // 鍵盤事件的 key
+	PreventDefault func()
+
+	raw any // 瀏覽器的事件物件，Script 處理器以其作為 event
+}
+
+// DOM 是 Root 對文件的所有操作；瀏覽器中由 Document() 提供（GOOS=js GOARCH=wasm），
+// 測試時可以假 DOM 取代
+type DOM interface {
+	CreateElement(tag string) Node
+	CreateText(text string) Node
+	SetText(n Node, text string)
+	// ParseHTML 將 HTML 片段解析為尚未插入文件的節點；VNode 的 Content 與 dom.Render 相同，視為 HTML
+	ParseHTML(html string) []Node
+	SetAttribute(el Node, name, value string)
+	RemoveAttribute(el Node, name string)
+	// InsertBefore 將 child 插入 parent 中 ref 之前；ref 為 nil 時附加到結尾
+	InsertBefore(parent, child, ref Node)
+	RemoveChild(parent, child Node)
+	ReplaceChild(parent, newChild, oldChild Node)
+	// Listen 為元素註冊事件監聽器，返回移除監聽器的函數
+	Listen(el Node, event string, handler func(Event)) (stop func())
+	// Script 將 JavaScript 代碼編譯為事件處理器，執行時 this 為 el、event 為事件物件（與內聯 onclick 相同）
+	Script(el Node, code string) func(Event)
+}
+
+// Root 是掛載在容器元素中的虛擬 DOM 樹
+// render 每次 Update 時重新呼叫，新舊 VNode 比對後只對 DOM 做必要的修改
+type Root struct {
+	dom       DOM
+	container Node
+	render    func() dom.VNode
+	tree      *mounted
+}
+
+// mounted 記錄已掛載的 VNode 與其對應的 DOM 節點
+type mounted struct {
+	vnode     dom.VNode
+	node      Node
+	content   []Node // Content 解析出的節點；文字 VNode（Tag 為空）在父節點中就是這些節點
+	children  []*mounted
+	listeners map[string]*listener
+}
+
+// listener 是元素上某個事件的監聽器；重新渲染時只替換 handler，不重新註冊
+type listener struct {
+	handler func(Event)
+	code    string // JSAction 處理器的代碼，用於判斷是否需要重新編譯
+	stop    func()
+}
+
+// Mount 在 container 中渲染 render() 的結果並返回 Root
+// 屬性與 dom.Render 相同的規則設定；事件屬性（onClick 等）可為：
+//   - dom.JSAction 或 string：JavaScript 代碼，與內聯處理器相同（this 為元素、event 為事件）
+//   - func() 或 func(wasm.Event)：Go 函數
+//
+// 元素建立後執行其 onDOMReady（若有）
+//
+// 用法：
+//
+//	var count int
+//	var root *wasm.Root
+//	root = wasm.Mount(wasm.Document(), container, func() dom.VNode {
+//	    return dom.Button(dom.Props{"onClick": func() { count++; root.Update() }}, fmt.Sprint(count))
+//	})
+func Mount(d DOM, container Node, render func() dom.VNode) *Root {
+	r := &Root{dom: d, container: container, render: render}
+	r.tree = r.create(render())
+	r.insert(container, r.tree, nil)
+	r.ready(r.tree)
+	return r
+}
+
+// Update 重新呼叫 render 並將差異套用到 DOM
+func (r *Root) Update() {
+	if r.tree == nil {
+		return
+	}
+	r.tree = r.patch(r.container, r.tree, r.render())
+}
+
+// Unmount 移除掛載的節點與所有事件監聽器
+func (r *Root) Unmount() {
+	if r.tree == nil {
+		return
+	}
+	r.release(r.tree)
+	r.remove(r.container, r.tree)
+	r.tree = nil
+}
+
+// create 建立 VNode 對應的 DOM 子樹
+func (r *Root) create(v dom.VNode) *mounted {
+	if v.Tag == "" {
+		return &mounted{vnode: v, content: r.parseContent(v.Content)}
+	}
+	m := &mounted{vnode: v, node: r.dom.CreateElement(v.Tag)}
+	r.setProps(m, nil, v.Props)
+	if v.Content != "" {
+		m.content = r.parseContent(v.Content)
+		for _, n := range m.content {
+			r.dom.InsertBefore(m.node, n, nil)
+		}
+	}
+	for _, c := range v.Children {
+		child := r.create(c)
+		m.children = append(m.children, child)
+		r.insert(m.node, child, nil)
+	}
+	return m
+}
+
+// parseContent 將 Content 轉為節點：純文字直接建立文字節點，含標記或字元參照時以 ParseHTML 解析
+// 返回的列表至少有一個節點，文字 VNode 才能在父節點中保有位置
+func (r *Root) parseContent(html string) []Node {
+	if isText(html) {
+		return []Node{r.dom.CreateText(html)}
+	}
+	nodes := r.dom.ParseHTML(html)
+	if len(nodes) == 0 {
+		nodes = []Node{r.dom.CreateText("")}
+	}
+	return nodes
+}
+
+// isText 判斷 Content 是否不含標記與字元參照，可以直接作為文字節點的內容
+func isText(html string) bool {
+	return !strings.ContainsAny(html, "<&")
+}
+
+// nodes 返回子樹在父節點中佔用的 DOM 節點
+func (m *mounted) nodes() []Node {
+	if m.vnode.Tag == "" {
+		return m.content
+	}
+	return []Node{m.node}
+}
+
+// insert 將子樹的節點插入 parent 中 ref 之前；ref 為 nil 時附加到結尾
+func (r *Root) insert(parent Node, m *mounted, ref Node) {
+	for _, n := range m.nodes() {
+		r.dom.InsertBefore(parent, n, ref)
+	}
+}
+
+// remove 從 parent 移除子樹的節點
+func (r *Root) remove(parent Node, m *mounted) {
+	for _, n := range m.nodes() {
+		r.dom.RemoveChild(parent, n)
+	}
+}
+
+// replaceNodes 以 next 取代 parent 中的 old
+func (r *Root) replaceNodes(parent Node, next, old []Node) {
+	if len(next) == 1 && len(old) == 1 {
+		r.dom.ReplaceChild(parent, next[0], old[0])
+		return
+	}
+	for _, n := range next {
+		r.dom.InsertBefore(parent, n, old[0])
+	}
+	for _, n := range old {
+		r.dom.RemoveChild(parent, n)
+	}
+}
+
+// ready 在新建的子樹插入文件後，依文件順序執行其中的 onDOMReady
+func (r *Root) ready(m *mounted) {
+	for _, c := range m.children {
+		r.ready(c)
+	}
+	if code := onDOMReady(m.vnode.Props); code != "" {
+		r.dom.Script(m.node, "("+code+")();")(Event{Type: "DOMContentLoaded", Target: m.node})
+	}
+}
+
+// patch 將已掛載的子樹更新為 v，返回更新後的記錄；無法就地更新時替換整個子樹
+func (r *Root) patch(parent Node, m *mounted, v dom.VNode) *mounted {
+	if m.vnode.Tag != v.Tag || onDOMReady(m.vnode.Props) != onDOMReady(v.Props) {
+		next := r.create(v)
+		r.replaceNodes(parent, next.nodes(), m.nodes())
+		r.release(m)
+		r.ready(next)
+		return next
+	}
+	if v.Tag == "" {
+		switch {
+		case m.vnode.Content == v.Content:
+		case len(m.content) == 1 && isText(m.vnode.Content) && isText(v.Content):
+			r.dom.SetText(m.content[0], v.Content)
+		default:
+			next := r.parseContent(v.Content)
+			r.replaceNodes(parent, next, m.content)
+			m.content = next
+		}
+		m.vnode = v
+		return m
+	}
+
+	r.setProps(m, m.vnode.Props, v.Props)
+	switch {
+	case m.vnode.Content == v.Content:
+	case len(m.content) == 1 && isText(m.vnode.Content) && isText(v.Content) && v.Content != "":
+		r.dom.SetText(m.content[0], v.Content)
+	default:
+		var next []Node
+		if v.Content != "" {
+			next = r.parseContent(v.Content)
+		}
+		var first Node
+		if len(m.children) > 0 {
+			first = m.children[0].nodes()[0]
+		}
+		for _, n := range next {
+			r.dom.InsertBefore(m.node, n, first)
+		}
+		for _, n := range m.content {
+			r.dom.RemoveChild(m.node, n)
+		}
+		m.content = next
+	}
+
+	// 子節點依位置比對：共同長度內逐一更新，多出的新子節點附加，多出的舊子節點移除
+	common := min(len(m.children), len(v.Children))
+	for i := 0; i < common; i++ {
+		m.children[i] = r.patch(m.node, m.children[i], v.Children[i])
+	}
+	for _, c := range v.Children[common:] {
+		child := r.create(c)
+		m.children = append(m.children, child)
+		r.insert(m.node, child, nil)
+		r.ready(child)
+	}
+	for _, child := range m.children[len(v.Children):] {
+		r.release(child)
+		r.remove(m.node, child)
+	}
+	m.children = m.children[:len(v.Children)]
+	m.vnode = v
+	return m
+}
+
+// release 移除子樹中所有的事件監聽器
+func (r *Root) release(m *mounted) {
+	for _, l := range m.listeners {
+		l.stop()
+	}
+	m.listeners = nil
+	for _, c := range m.children {
+		r.release(c)
+	}
+}
+
+// setProps 比對新舊屬性，只設定有變化的屬性與事件處理器
+func (r *Root) setProps(m *mounted, old, props dom.Props) {
+	oldAttrs := attributes(old)
+	newAttrs := attributes(props)
+	for _, name := range sortedNames(oldAttrs) {
+		if _, ok := newAttrs[name]; !ok {
+			r.dom.RemoveAttribute(m.node, name)
+		}
+	}
+	for _, name := range sortedNames(newAttrs) {
+		if value, ok := oldAttrs[name]; !ok || value != newAttrs[name] {
+			r.dom.SetAttribute(m.node, name, newAttrs[name])
+		}
+	}
+
+	handlers := make(map[string]any)
+	for k, v := range props {
+		if isEvent(k) {
+			if _, ok := v.(dom.ServerHandlerRef); !ok {
+				handlers[strings.ToLower(k[2:])] = v
+			}
+		}
+	}
+	for event, l := range m.listeners {
+		if _, ok := handlers[event]; !ok {
+			l.stop()
+			delete(m.listeners, event)
+		}
+	}
+	for _, event := range sortedNames(handlers) {
+		r.listen(m, event, handlers[event])
+	}
+}
+
+// listen 設定元素的事件處理器；已有監聽器時只替換其 handler
+func (r *Root) listen(m *mounted, event string, h any) {
+	l := m.listeners[event]
+	if l == nil {
+		l = &listener{}
+		l.stop = r.dom.Listen(m.node, event, func(ev Event) {
+			if l.handler != nil {
+				l.handler(ev)
+			}
+		})
+		if m.listeners == nil {
+			m.listeners = make(map[string]*listener)
+		}
+		m.listeners[event] = l
+	}
+
+	code := ""
+	switch t := h.(type) {
+	case dom.JSAction:
+		code = t.Code
+	case string:
+		code = t
+	case func():
+		l.handler, l.code = func(Event) { t() }, ""
+		return
+	case func(Event):
+		l.handler, l.code = t, ""
+		return
+	default:
+		l.handler, l.code = nil, ""
+		return
+	}
+	if l.handler == nil || l.code != code {
+		l.handler, l.code = r.dom.Script(m.node, code), code
+	}
+}
+
+// attributes 依 dom.Render 的規則計算元素的 HTML 屬性；事件處理器、onDOMReady 與保留屬性不包含在內
+func attributes(p dom.Props) map[string]string {
+	attrs := make(map[string]string, len(p))
+	for k, v := range p {
+		if k == "onDOMReady" || strings.HasPrefix(k, "gvd:") {
+			continue
+		}
+		if isEvent(k) {
+			if ref, ok := v.(dom.ServerHandlerRef); ok {
+				attrs["data-gvd-server-handler"] = ref.ID + "|" + strings.ToLower(k[2:])
+			}
+			continue
+		}
+		var value string
+		switch t := v.(type) {
+		case bool:
+			if !t {
+				continue
+			}
+			value = ""
+		case string:
+			value = t
+		case dom.JSAction:
+			value = t.Code
+		default:
+			value = fmt.Sprint(t)
+		}
+		if value == "false" {
+			continue
+		}
+		attrs[k] = value
+	}
+	return attrs
+}
+
+func isEvent(k string) bool {
+	return len(k) > 2 && strings.HasPrefix(k, "on") && k != "onDOMReady"
+}
+
+func onDOMReady(p dom.Props) string {
+	switch t := p["onDOMReady"].(type) {
+	case dom.JSAction:
+		return t.Code
+	case string:
+		return t
+	}
+	return ""
+}
+
+func sortedNames[V any](m map[string]V) []string {
+	names := make([]string, 0, len(m))
+	for k := range m {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
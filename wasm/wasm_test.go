@@ -0,0 +1,284 @@
+// wasm_test.go
+package wasm
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/TimLai666/go-vdom/dom"
+)
+
+// fakeNode 是假 DOM 的節點
+type fakeNode struct {
+	tag       string
+	text      string
+	attrs     map[string]string
+	children  []*fakeNode
+	listeners map[string]func(Event)
+}
+
+// fakeDOM 以記憶體中的樹實作 DOM，並記錄每個修改操作
+type fakeDOM struct {
+	ops     []string
+	scripts []string
+}
+
+func (d *fakeDOM) log(format string, args ...any) {
+	d.ops = append(d.ops, fmt.Sprintf(format, args...))
+}
+
+func (d *fakeDOM) CreateElement(tag string) Node {
+	d.log("create %s", tag)
+	return &fakeNode{tag: tag, attrs: map[string]string{}, listeners: map[string]func(Event){}}
+}
+
+func (d *fakeDOM) CreateText(text string) Node {
+	d.log("text %q", text)
+	return &fakeNode{text: text}
+}
+
+func (d *fakeDOM) SetText(n Node, text string) {
+	d.log("setText %q", text)
+	n.(*fakeNode).text = text
+}
+
+// ParseHTML 把第一個 '<' 之前的文字與其後的標記分為兩個節點，標記部分原樣保留
+func (d *fakeDOM) ParseHTML(html string) []Node {
+	d.log("parse %q", html)
+	var nodes []Node
+	i := strings.Index(html, "<")
+	if i < 0 {
+		i = len(html)
+	}
+	for _, part := range []string{html[:i], html[i:]} {
+		if part != "" {
+			nodes = append(nodes, &fakeNode{text: part})
+		}
+	}
+	return nodes
+}
+
+func (d *fakeDOM) SetAttribute(el Node, name, value string) {
+	d.log("set %s=%q", name, value)
+	el.(*fakeNode).attrs[name] = value
+}
+
+func (d *fakeDOM) RemoveAttribute(el Node, name string) {
+	d.log("remove %s", name)
+	delete(el.(*fakeNode).attrs, name)
+}
+
+func (d *fakeDOM) InsertBefore(parent, child, ref Node) {
+	p := parent.(*fakeNode)
+	if ref == nil {
+		d.log("append")
+		p.children = append(p.children, child.(*fakeNode))
+		return
+	}
+	d.log("insertBefore")
+	for i, c := range p.children {
+		if c == ref {
+			p.children = append(p.children[:i], append([]*fakeNode{child.(*fakeNode)}, p.children[i:]...)...)
+			return
+		}
+	}
+}
+
+func (d *fakeDOM) RemoveChild(parent, child Node) {
+	d.log("removeChild")
+	p := parent.(*fakeNode)
+	for i, c := range p.children {
+		if c == child {
+			p.children = append(p.children[:i], p.children[i+1:]...)
+			return
+		}
+	}
+}
+
+func (d *fakeDOM) ReplaceChild(parent, newChild, oldChild Node) {
+	d.log("replaceChild")
+	p := parent.(*fakeNode)
+	for i, c := range p.children {
+		if c == oldChild {
+			p.children[i] = newChild.(*fakeNode)
+		}
+	}
+}
+
+func (d *fakeDOM) Listen(el Node, event string, handler func(Event)) func() {
+	d.log("listen %s", event)
+	n := el.(*fakeNode)
+	n.listeners[event] = handler
+	return func() {
+		d.log("unlisten %s", event)
+		delete(n.listeners, event)
+	}
+}
+
+func (d *fakeDOM) Script(el Node, code string) func(Event) {
+	d.log("compile %q", code)
+	return func(Event) { d.scripts = append(d.scripts, code) }
+}
+
+// html 將假 DOM 樹序列化，便於比對
+func (n *fakeNode) html() string {
+	if n.tag == "" {
+		return n.text
+	}
+	var sb strings.Builder
+	sb.WriteString("<" + n.tag)
+	for _, k := range sortedNames(n.attrs) {
+		sb.WriteString(fmt.Sprintf(" %s=%q", k, n.attrs[k]))
+	}
+	sb.WriteString(">")
+	for _, c := range n.children {
+		sb.WriteString(c.html())
+	}
+	sb.WriteString("</" + n.tag + ">")
+	return sb.String()
+}
+
+func (d *fakeDOM) take() []string {
+	ops := d.ops
+	d.ops = nil
+	return ops
+}
+
+func TestMountAndUpdate(t *testing.T) {
+	d := &fakeDOM{}
+	container := &fakeNode{tag: "main", attrs: map[string]string{}}
+
+	count := 0
+	items := []string{"a"}
+	var root *Root
+	root = Mount(d, container, func() dom.VNode {
+		var lis []dom.VNode
+		for _, it := range items {
+			lis = append(lis, dom.Li(nil, it))
+		}
+		return dom.Div(dom.Props{"id": "app", "class": fmt.Sprintf("n%d", count), "hidden": count > 2},
+			dom.Button(dom.Props{"onClick": func() { count++; root.Update() }}, fmt.Sprintf("count: %d", count)),
+			dom.Ul(nil, lis),
+		)
+	})
+
+	if got, want := container.html(), `<main><div class="n0" id="app"><button>count: 0</button><ul><li>a</li></ul></div></main>`; got != want {
+		t.Fatalf("mounted:\n%s\nwant\n%s", got, want)
+	}
+	d.take()
+
+	// Go 處理器：只更新有變化的屬性與文字
+	button := container.children[0].children[0]
+	button.listeners["click"](Event{Type: "click"})
+	if got, want := d.take(), []string{`set class="n1"`, `setText "count: 1"`}; !reflect.DeepEqual(got, want) {
+		t.Errorf("click ops = %q, want %q", got, want)
+	}
+
+	items = []string{"a", "b", "c"}
+	root.Update()
+	if got, want := d.take(), []string{`create li`, `text "b"`, `append`, `append`, `create li`, `text "c"`, `append`, `append`}; !reflect.DeepEqual(got, want) {
+		t.Errorf("append ops = %q, want %q", got, want)
+	}
+
+	items = []string{"a"}
+	count = 3
+	root.Update()
+	if got, want := d.take(), []string{`set class="n3"`, `set hidden=""`, `setText "count: 3"`, `removeChild`, `removeChild`}; !reflect.DeepEqual(got, want) {
+		t.Errorf("shrink ops = %q, want %q", got, want)
+	}
+	if got, want := container.html(), `<main><div class="n3" hidden="" id="app"><button>count: 3</button><ul><li>a</li></ul></div></main>`; got != want {
+		t.Errorf("after updates:\n%s\nwant\n%s", got, want)
+	}
+
+	root.Unmount()
+	if len(container.children) != 0 || len(button.listeners) != 0 {
+		t.Errorf("Unmount should remove nodes and listeners: %s %v", container.html(), button.listeners)
+	}
+}
+
+func TestJSActionHandlersAndOnDOMReady(t *testing.T) {
+	d := &fakeDOM{}
+	container := &fakeNode{tag: "main", attrs: map[string]string{}}
+
+	code := "this.textContent='clicked'"
+	useGo := false
+	var calls int
+	root := Mount(d, container, func() dom.VNode {
+		props := dom.Props{"onClick": dom.JSAction{Code: code}, "onDOMReady": dom.JSAction{Code: "function(){init()}"}}
+		if useGo {
+			props["onClick"] = func(ev Event) { calls++ }
+		}
+		return dom.Span(props, "x")
+	})
+	if want := []string{"(function(){init()})();"}; !reflect.DeepEqual(d.scripts, want) {
+		t.Errorf("onDOMReady scripts = %q, want %q", d.scripts, want)
+	}
+
+	span := container.children[0]
+	span.listeners["click"](Event{Type: "click"})
+	if d.scripts[len(d.scripts)-1] != code {
+		t.Errorf("JSAction handler not called: %q", d.scripts)
+	}
+
+	// 相同的代碼不重新編譯，處理器替換為 Go 函數時不重新註冊監聽器
+	d.take()
+	root.Update()
+	if ops := d.take(); len(ops) != 0 {
+		t.Errorf("unchanged render should not touch the DOM: %q", ops)
+	}
+	useGo = true
+	root.Update()
+	if ops := d.take(); len(ops) != 0 {
+		t.Errorf("swapping handlers should not touch the DOM: %q", ops)
+	}
+	span.listeners["click"](Event{Type: "click"})
+	if calls != 1 {
+		t.Errorf("Go handler calls = %d, want 1", calls)
+	}
+}
+
+func TestContentIsHTML(t *testing.T) {
+	d := &fakeDOM{}
+	container := &fakeNode{tag: "main", attrs: map[string]string{}}
+
+	// 與 dom.Render 相同，Content 視為 HTML 而不是純文字
+	label := "a <b>x</b>"
+	text := "t &amp; <i>u</i>"
+	render := func() dom.VNode {
+		return dom.Div(nil, dom.P(nil, label), dom.VNode{Content: text})
+	}
+	root := Mount(d, container, render)
+	if got, want := container.html(), "<main>"+dom.Render(render())+"</main>"; got != want {
+		t.Fatalf("mounted:\n%s\nwant\n%s", got, want)
+	}
+	if ops := d.take(); !slices.Contains(ops, `parse "a <b>x</b>"`) || slices.Contains(ops, `text "a <b>x</b>"`) {
+		t.Errorf("markup should be parsed, not set as text: %q", ops)
+	}
+
+	// 從 HTML 改為純文字時替換解析出的所有節點
+	label, text = "plain", "v"
+	root.Update()
+	if got, want := container.html(), "<main>"+dom.Render(render())+"</main>"; got != want {
+		t.Errorf("after update:\n%s\nwant\n%s", got, want)
+	}
+	p := container.children[0].children[0]
+	if len(p.children) != 1 || len(container.children[0].children) != 2 {
+		t.Errorf("stale content nodes left behind: %s", container.html())
+	}
+
+	label, text = "<em>e</em>", "w"
+	root.Update()
+	if got, want := container.html(), "<main>"+dom.Render(render())+"</main>"; got != want {
+		t.Errorf("after second update:\n%s\nwant\n%s", got, want)
+	}
+	d.take()
+	label = "<em>e</em>"
+	text = "z"
+	root.Update()
+	if got, want := d.take(), []string{`setText "z"`}; !reflect.DeepEqual(got, want) {
+		t.Errorf("plain text should still be patched in place: %q", got)
+	}
+}
@@ -0,0 +1,133 @@
+// prelude.go
+package webcomponent
+
+// prelude 是 Bundle 輸出的共用程式碼：模板插值與自訂元素基底類別
+// define(tag, spec) 的 spec 包含 html（模板）、defaults（預設 props）與 init（onDOMReady 代碼）
+const prelude = `
+  var counter = 0;
+
+  // camelCase prop 對應 kebab-case 屬性
+  function attrName(prop) {
+    return prop.replace(/[A-Z]/g, function(c, i) { return (i ? '-' : '') + c.toLowerCase(); });
+  }
+
+  // 依預設值的型別轉換屬性字串
+  function coerce(value, def) {
+    switch (typeof def) {
+      case 'boolean': return value === '' || value === 'true';
+      case 'number': return Number(value);
+      case 'object':
+        try { return JSON.parse(value); } catch (e) { return def; }
+      default: return value;
+    }
+  }
+
+  function replaceVars(s, p, json) {
+    return s.replace(/\{\{(.+?)\}\}/g, function(_, key) {
+      var v = p[key.trim()];
+      if (json) return v === undefined ? 'null' : JSON.stringify(v);
+      if (v === undefined || v === null) return '';
+      return typeof v === 'object' ? JSON.stringify(v) : String(v);
+    });
+  }
+
+  // 與 Go 端相同的插值規則：先求值 ${...}（其中的 {{key}} 以 JSON 代入），再替換其餘 {{key}}
+  function interpolate(s, p, json) {
+    var out = '', i = 0;
+    for (;;) {
+      var start = s.indexOf('${', i);
+      if (start < 0) break;
+      var depth = 1, j = start + 2;
+      for (; j < s.length && depth > 0; j++) {
+        if (s[j] === '{') depth++;
+        else if (s[j] === '}') depth--;
+      }
+      if (depth > 0) break;
+      var expr = replaceVars(s.slice(start + 2, j - 1), p, true), value;
+      try {
+        value = String(new Function('return (' + expr + ');')());
+      } catch (e) {
+        value = '';
+      }
+      out += s.slice(i, start) + value;
+      i = j;
+    }
+    return replaceVars(out + s.slice(i), p, json);
+  }
+
+  // 找出模板中含有 {{...}} 的文字節點與屬性
+  function collectBindings(root) {
+    var bindings = [];
+    var walker = document.createTreeWalker(root, NodeFilter.SHOW_ELEMENT | NodeFilter.SHOW_TEXT);
+    while (walker.nextNode()) {
+      var node = walker.currentNode;
+      if (node.nodeType === 3) {
+        if (node.nodeValue.indexOf('{{') >= 0) bindings.push({ node: node, source: node.nodeValue });
+        continue;
+      }
+      Array.prototype.forEach.call(node.attributes, function(attr) {
+        if (attr.value.indexOf('{{') >= 0) bindings.push({ el: node, attr: attr.name, source: attr.value });
+      });
+    }
+    return bindings;
+  }
+
+  function define(tag, spec) {
+    if (customElements.get(tag)) return;
+    var template = document.createElement('template');
+    template.innerHTML = spec.html;
+    var props = Object.keys(spec.defaults);
+
+    customElements.define(tag, class extends HTMLElement {
+      static get observedAttributes() {
+        return props.map(attrName);
+      }
+
+      props() {
+        var p = {}, self = this;
+        props.forEach(function(key) {
+          var value = self.getAttribute(attrName(key));
+          p[key] = value === null ? spec.defaults[key] : coerce(value, spec.defaults[key]);
+        });
+        if (!p.id) p.id = this._gvdId || (this._gvdId = tag + '-' + (++counter));
+        return p;
+      }
+
+      connectedCallback() {
+        if (!this.shadowRoot) {
+          this.attachShadow({ mode: 'open' }).appendChild(template.content.cloneNode(true));
+          this._bindings = collectBindings(this.shadowRoot);
+        }
+        this.update();
+        if (spec.init && !this._gvdReady) {
+          this._gvdReady = true;
+          try {
+            new Function('root', 'return (' + interpolate(spec.init, this.props(), true) + ');')(this.shadowRoot)();
+          } catch (err) {
+            console.error('gvd: ' + tag + ' init failed', err);
+          }
+        }
+      }
+
+      attributeChangedCallback() {
+        if (this._bindings) this.update();
+      }
+
+      // 以目前的屬性重新插值模板；值為 "false" 的屬性與 Go 端渲染一樣省略
+      update() {
+        var p = this.props();
+        this._bindings.forEach(function(b) {
+          var value = interpolate(b.source, p, false);
+          if (!b.el) {
+            b.node.nodeValue = value;
+          } else if (value === 'false') {
+            b.el.removeAttribute(b.attr);
+          } else {
+            b.el.setAttribute(b.attr, value);
+          }
+        });
+      }
+    });
+  }
+
+`
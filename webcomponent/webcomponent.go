@@ -0,0 +1,157 @@
+// webcomponent.go
+package webcomponent
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/TimLai666/go-vdom/dom"
+)
+
+// Element 指定要匯出的組件與其自訂元素名稱
+type Element struct {
+	Tag       string // 自訂元素名稱，必須為小寫且包含 "-"，例如 "gvd-card"
+	Component func(props dom.Props, children ...dom.VNode) dom.VNode
+}
+
+var (
+	tagPattern         = regexp.MustCompile(`^[a-z][a-z0-9._]*-[a-z0-9._-]*$`)
+	templateVarPattern = regexp.MustCompile(`\{\{(.+?)\}\}`)
+)
+
+// Define 返回將單一組件註冊為自訂元素的 JavaScript
+func Define(tag string, component func(props dom.Props, children ...dom.VNode) dom.VNode) (string, error) {
+	return Bundle(Element{Tag: tag, Component: component})
+}
+
+// Bundle 返回將多個組件註冊為自訂元素的 JavaScript，可直接以 <script> 載入，不依賴 go-vdom runtime
+//   - 組件模板成為 <template>，以 shadow DOM 渲染
+//   - 模板中的 {{prop}} 成為觀察的屬性（camelCase 的 prop 對應 kebab-case 屬性，如 accentColor → accent-color），
+//     屬性改變時重新插值；${...} 表達式在瀏覽器中求值
+//   - {{children}} 成為 <slot>
+//   - onDOMReady 代碼在第一次 connectedCallback 時執行，其中的 document.getElementById / querySelector
+//     改為在 shadow root 中查找
+//
+// 只支援直接由 dom.Component 建立的組件；在 Component 外再計算衍生 props 的包裝函數
+// （如 components.Btn）無法取得其計算邏輯
+func Bundle(elements ...Element) (string, error) {
+	var sb strings.Builder
+	sb.WriteString("// 由 go-vdom 產生，請勿手動修改\n(function() {\n")
+	sb.WriteString(prelude)
+
+	var errs []error
+	seen := make(map[string]bool)
+	for _, el := range elements {
+		def, err := definition(el)
+		if err == nil && seen[el.Tag] {
+			err = fmt.Errorf("webcomponent: duplicate tag %q", el.Tag)
+		}
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		seen[el.Tag] = true
+		sb.WriteString(def)
+	}
+	if err := errors.Join(errs...); err != nil {
+		return "", err
+	}
+
+	sb.WriteString("})();\n")
+	return sb.String(), nil
+}
+
+// definition 返回單一元素的 define(...) 呼叫
+func definition(el Element) (string, error) {
+	if !tagPattern.MatchString(el.Tag) {
+		return "", fmt.Errorf("webcomponent: invalid custom element name %q", el.Tag)
+	}
+	if el.Component == nil {
+		return "", fmt.Errorf("webcomponent: %s: nil component", el.Tag)
+	}
+	spec, ok := dom.SpecOf(el.Component(dom.Props{}))
+	if !ok {
+		return "", fmt.Errorf("webcomponent: %s: not created by dom.Component", el.Tag)
+	}
+
+	defaults := make(map[string]any)
+	for _, key := range templateKeys(spec.Template, spec.OnDOMReady) {
+		defaults[key] = ""
+	}
+	for k, v := range spec.Defaults {
+		defaults[k] = v
+	}
+	delete(defaults, "children")
+
+	var init string
+	if code := strings.TrimSpace(spec.OnDOMReady); code != "" {
+		// onDOMReady 代碼以 document 查找組件內的元素；在 shadow DOM 中改為查找 shadow root
+		init = dom.ShadowLookup(code, "root")
+	}
+
+	data, err := json.Marshal(map[string]any{
+		"html":     dom.Render(withSlots(spec.Template)),
+		"defaults": defaults,
+		"init":     init,
+	})
+	if err != nil {
+		return "", fmt.Errorf("webcomponent: %s: %w", el.Tag, err)
+	}
+	return fmt.Sprintf("define(%q, %s);\n", el.Tag, data), nil
+}
+
+// withSlots 將模板中的 {{children}} 文字節點替換為 <slot>
+func withSlots(v dom.VNode) dom.VNode {
+	if v.Tag == "" {
+		if strings.TrimSpace(v.Content) == "{{children}}" {
+			return dom.VNode{Tag: "slot"}
+		}
+		return v
+	}
+	out := v
+	out.Children = make([]dom.VNode, len(v.Children))
+	for i, c := range v.Children {
+		out.Children[i] = withSlots(c)
+	}
+	return out
+}
+
+// templateKeys 返回模板與額外代碼中引用的所有 prop 名稱（排序後）
+func templateKeys(v dom.VNode, extra ...string) []string {
+	seen := make(map[string]bool)
+	var walk func(n dom.VNode)
+	collect := func(s string) {
+		for _, m := range templateVarPattern.FindAllStringSubmatch(s, -1) {
+			seen[strings.TrimSpace(m[1])] = true
+		}
+	}
+	walk = func(n dom.VNode) {
+		collect(n.Content)
+		for _, val := range n.Props {
+			switch t := val.(type) {
+			case string:
+				collect(t)
+			case dom.JSAction:
+				collect(t.Code)
+			}
+		}
+		for _, c := range n.Children {
+			walk(c)
+		}
+	}
+	walk(v)
+	for _, s := range extra {
+		collect(s)
+	}
+
+	keys := make([]string, 0, len(seen))
+	for k := range seen {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
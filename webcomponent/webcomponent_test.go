@@ -0,0 +1,67 @@
+// webcomponent_test.go
+package webcomponent
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/TimLai666/go-vdom/dom"
+)
+
+var badge = dom.Component(
+	dom.Span(dom.Props{"id": "{{id}}", "class": "badge", "data-tone": "{{tone}}"},
+		dom.Code(nil, "{{label}}"),
+		"{{children}}",
+	),
+	&dom.JSAction{Code: "function(){document.getElementById('{{id}}').title={{accentColor}};}"},
+	dom.PropsDefault{"tone": "info", "accentColor": "#000", "count": 0},
+)
+
+func TestBundle(t *testing.T) {
+	js, err := Bundle(Element{Tag: "gvd-badge", Component: badge})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"customElements.define(tag",
+		`define("gvd-badge", `,
+		`\u003cslot\u003e\u003c/slot\u003e`,
+		`"defaults":{"accentColor":"#000","count":0,"id":"","label":"","tone":"info"}`,
+		"root.getElementById('{{id}}')",
+	} {
+		if !strings.Contains(js, want) {
+			t.Errorf("bundle missing %s:\n%s", want, js)
+		}
+	}
+	if strings.Contains(js, "document.getElementById('{{id}}')") {
+		t.Error("document lookups in onDOMReady should be rewritten to the shadow root")
+	}
+	if strings.Contains(js, `"children"`) {
+		t.Error("children must be projected through <slot>, not observed as an attribute")
+	}
+}
+
+func TestBundleErrors(t *testing.T) {
+	plain := func(props dom.Props, children ...dom.VNode) dom.VNode { return dom.Div(nil) }
+
+	_, err := Bundle(
+		Element{Tag: "Badge", Component: badge},
+		Element{Tag: "gvd-badge", Component: badge},
+		Element{Tag: "gvd-badge", Component: badge},
+		Element{Tag: "gvd-plain", Component: plain},
+		Element{Tag: "gvd-nil"},
+	)
+	if err == nil {
+		t.Fatal("expected errors")
+	}
+	for _, want := range []string{
+		`invalid custom element name "Badge"`,
+		`duplicate tag "gvd-badge"`,
+		"gvd-plain: not created by dom.Component",
+		"gvd-nil: nil component",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q missing %q", err, want)
+		}
+	}
+}